@@ -11,11 +11,11 @@
 package ims
 
 import (
-	"crypto/sha256"
-	"encoding/base64"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GrantType is the grant type specified when building an authorization URL.
@@ -32,6 +32,32 @@ const (
 	GrantTypeDevice
 )
 
+// Values for AuthorizeURLConfig.Prompt.
+const (
+	// PromptNone requires that no user interface is displayed. It can't be
+	// combined with any other value.
+	PromptNone = "none"
+	// PromptLogin forces the user to re-authenticate.
+	PromptLogin = "login"
+	// PromptConsent forces the consent screen to be displayed.
+	PromptConsent = "consent"
+	// PromptSelectAccount forces the user to select an account.
+	PromptSelectAccount = "select_account"
+)
+
+// Values for AuthorizeURLConfig.ResponseMode.
+const (
+	// ResponseModeQuery returns the authorization response in the query
+	// string of the redirect URI.
+	ResponseModeQuery = "query"
+	// ResponseModeFragment returns the authorization response in the
+	// fragment of the redirect URI.
+	ResponseModeFragment = "fragment"
+	// ResponseModeFormPost returns the authorization response as a form
+	// POSTed to the redirect URI.
+	ResponseModeFormPost = "form_post"
+)
+
 // AuthorizeURLConfig is the configuration for building an authorization URL.
 type AuthorizeURLConfig struct {
 	ClientID     string
@@ -41,24 +67,83 @@ type AuthorizeURLConfig struct {
 	State        string
 	CodeVerifier string
 	Resource     []string
+	// Prompt is the list of prompts to force on the user, e.g. PromptLogin
+	// or PromptSelectAccount. Optional.
+	Prompt []string
+	// LoginHint is a hint about the login identifier the user might use,
+	// e.g. an email address. Optional.
+	LoginHint string
+	// Locale is the preferred locale for the login pages, e.g. en_US.
+	// Optional.
+	Locale string
+	// Nonce is a value bound to the ID token to mitigate replay attacks.
+	// Optional.
+	Nonce string
+	// ResponseMode is how the authorization response is returned to the
+	// redirect URI, e.g. ResponseModeFormPost. Optional.
+	ResponseMode string
+	// MaxAge is the maximum time elapsed since the last active
+	// authentication of the user. If the time is exceeded, the user is
+	// forced to re-authenticate. A zero value forces re-authentication.
+	// Fractions of a second are rounded up. If nil, the parameter is omitted.
+	MaxAge *time.Duration
+	// ACRValues is the list of requested authentication context class
+	// references, in order of preference. Optional.
+	ACRValues []string
+	// IDPFlow is a hint about the identity provider flow to use, e.g. a
+	// social login flow. Optional.
+	IDPFlow string
+	// ProviderID is a hint about the identity provider to use. Optional.
+	ProviderID string
+	// ExtraParams are additional parameters added verbatim to the
+	// authorization URL. They can't override any of the parameters above.
+	ExtraParams map[string]string
 }
 
 // AuthorizeURL builds an authorization URL according to the provided configuration.
 func (c *Client) AuthorizeURL(cfg *AuthorizeURLConfig) (string, error) {
+	q, err := authorizeParams(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	apiURL, err := url.Parse(fmt.Sprintf("%s/ims/authorize/v1", c.url))
+	if err != nil {
+		return "", fmt.Errorf("parse URL: %v", err)
+	}
+
+	apiURL.RawQuery = q.Encode()
+
+	return apiURL.String(), nil
+}
+
+// authorizeParams validates the configuration and builds the parameters of
+// an authorization request.
+func authorizeParams(cfg *AuthorizeURLConfig) (url.Values, error) {
 	if cfg.ClientID == "" {
-		return "", fmt.Errorf("missing client ID")
+		return nil, fmt.Errorf("missing client ID")
 	}
 
 	if len(cfg.Scope) == 0 {
-		return "", fmt.Errorf("missing scope")
+		return nil, fmt.Errorf("missing scope")
 	}
 
-	apiURL, err := url.Parse(fmt.Sprintf("%s/ims/authorize/v1", c.url))
-	if err != nil {
-		return "", fmt.Errorf("parse URL: %v", err)
+	if err := validatePrompt(cfg.Prompt); err != nil {
+		return nil, err
 	}
 
-	q := apiURL.Query()
+	switch cfg.ResponseMode {
+	case "", ResponseModeQuery, ResponseModeFragment, ResponseModeFormPost:
+		// Valid response mode.
+	default:
+		return nil, fmt.Errorf("invalid response mode: %v", cfg.ResponseMode)
+	}
+
+	if cfg.MaxAge != nil && *cfg.MaxAge < 0 {
+		return nil, fmt.Errorf("invalid max age: %v", *cfg.MaxAge)
+	}
+
+	q := url.Values{}
 
 	q.Set("client_id", cfg.ClientID)
 	q.Set("scope", strings.Join(cfg.Scope, ","))
@@ -83,20 +168,110 @@ func (c *Client) AuthorizeURL(cfg *AuthorizeURLConfig) (string, error) {
 		q.Set("state", cfg.State)
 	}
 
-	// SHA256 the code verifier and base64 encode it.
+	// Send the S256 challenge of the code verifier.
 	if cfg.CodeVerifier != "" {
-		h := sha256.New()
-		h.Write([]byte(cfg.CodeVerifier))
-		codeChallenge := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
-		q.Set("code_challenge", codeChallenge)
-		q.Set("code_challenge_method", "S256")
+		q.Set("code_challenge", CodeChallenge(cfg.CodeVerifier))
+		q.Set("code_challenge_method", CodeChallengeMethodS256)
 	}
 
 	for _, res := range cfg.Resource {
 		q.Add("resource", res)
 	}
 
-	apiURL.RawQuery = q.Encode()
+	if len(cfg.Prompt) > 0 {
+		q.Set("prompt", strings.Join(cfg.Prompt, " "))
+	}
 
-	return apiURL.String(), nil
+	if cfg.LoginHint != "" {
+		q.Set("login_hint", cfg.LoginHint)
+	}
+
+	if cfg.Locale != "" {
+		q.Set("locale", cfg.Locale)
+	}
+
+	if cfg.Nonce != "" {
+		q.Set("nonce", cfg.Nonce)
+	}
+
+	if cfg.ResponseMode != "" {
+		q.Set("response_mode", cfg.ResponseMode)
+	}
+
+	if cfg.MaxAge != nil {
+		// Round up, so that a sub-second value doesn't force re-authentication.
+		seconds := (*cfg.MaxAge + time.Second - 1) / time.Second
+		q.Set("max_age", strconv.FormatInt(int64(seconds), 10))
+	}
+
+	if len(cfg.ACRValues) > 0 {
+		q.Set("acr_values", strings.Join(cfg.ACRValues, " "))
+	}
+
+	if cfg.IDPFlow != "" {
+		q.Set("idp_flow", cfg.IDPFlow)
+	}
+
+	if cfg.ProviderID != "" {
+		q.Set("provider_id", cfg.ProviderID)
+	}
+
+	for k, v := range cfg.ExtraParams {
+		if k == "" {
+			return nil, fmt.Errorf("empty extra parameter name")
+		}
+		if reservedAuthorizeParams[k] {
+			return nil, fmt.Errorf("extra parameter conflicts with a standard parameter: %v", k)
+		}
+		q.Set(k, v)
+	}
+
+	return q, nil
+}
+
+// reservedAuthorizeParams are the parameters that can only be set through the
+// fields of AuthorizeURLConfig, even when the corresponding field is empty.
+var reservedAuthorizeParams = map[string]bool{
+	"client_id":             true,
+	"scope":                 true,
+	"response_type":         true,
+	"redirect_uri":          true,
+	"state":                 true,
+	"code_challenge":        true,
+	"code_challenge_method": true,
+	"resource":              true,
+	"prompt":                true,
+	"login_hint":            true,
+	"locale":                true,
+	"nonce":                 true,
+	"response_mode":         true,
+	"max_age":               true,
+	"acr_values":            true,
+	"idp_flow":              true,
+	"provider_id":           true,
+}
+
+func validatePrompt(prompt []string) error {
+	seen := map[string]bool{}
+
+	for _, p := range prompt {
+		switch p {
+		case PromptNone, PromptLogin, PromptConsent, PromptSelectAccount:
+			// Valid prompt.
+		default:
+			return fmt.Errorf("invalid prompt: %v", p)
+		}
+
+		if seen[p] {
+			return fmt.Errorf("duplicate prompt: %v", p)
+		}
+
+		seen[p] = true
+	}
+
+	if seen[PromptNone] && len(prompt) > 1 {
+		return fmt.Errorf("prompt %v can't be combined with other values", PromptNone)
+	}
+
+	return nil
 }
@@ -13,6 +13,7 @@ package ims_test
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -157,3 +158,191 @@ func TestAuthorizeURLNoScope(t *testing.T) {
 		t.Fatalf("invalid error: %v", err)
 	}
 }
+
+func TestAuthorizeURLExtendedParams(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{
+		URL: "http://ims.endpoint",
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	maxAge := 5 * time.Minute
+
+	u, err := c.AuthorizeURL(&ims.AuthorizeURLConfig{
+		ClientID:     "clientID",
+		Scope:        []string{"openid"},
+		Prompt:       []string{ims.PromptLogin, ims.PromptSelectAccount},
+		LoginHint:    "user@example.com",
+		Locale:       "fr_FR",
+		Nonce:        "nonce-value",
+		ResponseMode: ims.ResponseModeFormPost,
+		MaxAge:       &maxAge,
+		ACRValues:    []string{"mfa", "pwd"},
+		IDPFlow:      "social.google",
+		ProviderID:   "provider",
+		ExtraParams:  map[string]string{"dctx_id": "context"},
+	})
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	authURL, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	q := authURL.Query()
+
+	for name, want := range map[string]string{
+		"prompt":        "login select_account",
+		"login_hint":    "user@example.com",
+		"locale":        "fr_FR",
+		"nonce":         "nonce-value",
+		"response_mode": "form_post",
+		"max_age":       "300",
+		"acr_values":    "mfa pwd",
+		"idp_flow":      "social.google",
+		"provider_id":   "provider",
+		"dctx_id":       "context",
+	} {
+		if v := q.Get(name); v != want {
+			t.Errorf("invalid %s: %v", name, v)
+		}
+	}
+}
+
+func TestAuthorizeURLZeroMaxAge(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{
+		URL: "http://ims.endpoint",
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	var maxAge time.Duration
+
+	u, err := c.AuthorizeURL(&ims.AuthorizeURLConfig{
+		ClientID: "clientID",
+		Scope:    []string{"openid"},
+		MaxAge:   &maxAge,
+	})
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	authURL, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if v := authURL.Query()["max_age"]; len(v) != 1 || v[0] != "0" {
+		t.Fatalf("invalid max age: %v", v)
+	}
+}
+
+func TestAuthorizeURLSubSecondMaxAge(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{
+		URL: "http://ims.endpoint",
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tests := []struct {
+		maxAge time.Duration
+		want   string
+	}{
+		{time.Millisecond, "1"},
+		{1500 * time.Millisecond, "2"},
+		{2 * time.Second, "2"},
+	}
+
+	for _, tt := range tests {
+		maxAge := tt.maxAge
+
+		u, err := c.AuthorizeURL(&ims.AuthorizeURLConfig{
+			ClientID: "clientID",
+			Scope:    []string{"openid"},
+			MaxAge:   &maxAge,
+		})
+		if err != nil {
+			t.Fatalf("authorize: %v", err)
+		}
+
+		authURL, err := url.Parse(u)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+
+		if v := authURL.Query().Get("max_age"); v != tt.want {
+			t.Fatalf("invalid max age for %v: %v", tt.maxAge, v)
+		}
+	}
+}
+
+func TestAuthorizeURLInvalidExtendedParams(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{
+		URL: "http://ims.endpoint",
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	negative := -time.Second
+
+	tests := []struct {
+		name    string
+		cfg     *ims.AuthorizeURLConfig
+		wantErr string
+	}{
+		{
+			name:    "unknown prompt",
+			cfg:     &ims.AuthorizeURLConfig{Prompt: []string{"bogus"}},
+			wantErr: "invalid prompt: bogus",
+		},
+		{
+			name:    "duplicate prompt",
+			cfg:     &ims.AuthorizeURLConfig{Prompt: []string{ims.PromptLogin, ims.PromptLogin}},
+			wantErr: "duplicate prompt: login",
+		},
+		{
+			name:    "prompt none combined",
+			cfg:     &ims.AuthorizeURLConfig{Prompt: []string{ims.PromptNone, ims.PromptLogin}},
+			wantErr: "prompt none can't be combined with other values",
+		},
+		{
+			name:    "unknown response mode",
+			cfg:     &ims.AuthorizeURLConfig{ResponseMode: "web_message"},
+			wantErr: "invalid response mode: web_message",
+		},
+		{
+			name:    "negative max age",
+			cfg:     &ims.AuthorizeURLConfig{MaxAge: &negative},
+			wantErr: "invalid max age: -1s",
+		},
+		{
+			name:    "extra param overrides standard param",
+			cfg:     &ims.AuthorizeURLConfig{ExtraParams: map[string]string{"state": "forged"}},
+			wantErr: "extra parameter conflicts with a standard parameter: state",
+		},
+		{
+			name:    "extra param without name",
+			cfg:     &ims.AuthorizeURLConfig{ExtraParams: map[string]string{"": "value"}},
+			wantErr: "empty extra parameter name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ClientID = "clientID"
+			tt.cfg.Scope = []string{"openid"}
+
+			if _, err := c.AuthorizeURL(tt.cfg); err == nil {
+				t.Fatalf("expected error")
+			} else if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
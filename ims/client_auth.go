@@ -0,0 +1,169 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// defaultClientAssertionLifetime is the lifetime of client assertions when
+// PrivateKeyJWT.Lifetime is not set.
+const defaultClientAssertionLifetime = 5 * time.Minute
+
+// ClientAuthRequest contains the parts of a token endpoint request that can
+// be modified to authenticate the client.
+type ClientAuthRequest struct {
+	// ClientID is the client ID.
+	ClientID string
+	// Endpoint is the URL of the token endpoint, without query parameters.
+	Endpoint string
+	// Form contains the parameters sent in the body of the request.
+	Form url.Values
+	// Header contains the headers sent with the request.
+	Header http.Header
+}
+
+// ClientAuthenticator is a method to authenticate the client to the token
+// endpoint.
+type ClientAuthenticator interface {
+	// AuthenticateClient adds the client credentials to the request.
+	AuthenticateClient(r *ClientAuthRequest) error
+}
+
+// ClientSecretPost sends the client secret in the body of the request. This is
+// the client_secret_post method, and the default one when only a client secret
+// is provided.
+type ClientSecretPost struct {
+	// ClientSecret is the client secret. This field is required.
+	ClientSecret string
+}
+
+// AuthenticateClient implements ClientAuthenticator.
+func (a *ClientSecretPost) AuthenticateClient(r *ClientAuthRequest) error {
+	if a.ClientSecret == "" {
+		return fmt.Errorf("missing client secret")
+	}
+
+	r.Form.Set("client_secret", a.ClientSecret)
+
+	return nil
+}
+
+// ClientSecretBasic sends the client ID and the client secret with HTTP Basic
+// authentication. This is the client_secret_basic method.
+type ClientSecretBasic struct {
+	// ClientSecret is the client secret. This field is required.
+	ClientSecret string
+}
+
+// AuthenticateClient implements ClientAuthenticator.
+func (a *ClientSecretBasic) AuthenticateClient(r *ClientAuthRequest) error {
+	if a.ClientSecret == "" {
+		return fmt.Errorf("missing client secret")
+	}
+
+	// RFC 6749, section 2.3.1, requires the credentials to be form-encoded
+	// before being joined.
+
+	credentials := url.QueryEscape(r.ClientID) + ":" + url.QueryEscape(a.ClientSecret)
+
+	r.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+
+	return nil
+}
+
+// PrivateKeyJWT authenticates the client with a JWT assertion signed by a
+// private key registered for the client. This is the private_key_jwt method
+// (RFC 7523).
+type PrivateKeyJWT struct {
+	// Signer signs the assertion. Both RSA and ECDSA keys are supported. This
+	// field is required.
+	Signer crypto.Signer
+	// Algorithm is the JWS algorithm, e.g. RS256 or ES256. If not provided,
+	// it is derived from the key: RS256 for RSA keys, and the ES algorithm
+	// matching the curve for ECDSA keys.
+	Algorithm string
+	// KeyID is added to the header of the assertion, if not empty.
+	KeyID string
+	// Lifetime is the lifetime of the assertion. If not provided, it defaults
+	// to five minutes.
+	Lifetime time.Duration
+}
+
+// AuthenticateClient implements ClientAuthenticator.
+func (a *PrivateKeyJWT) AuthenticateClient(r *ClientAuthRequest) error {
+	jti, err := randomString(16)
+	if err != nil {
+		return fmt.Errorf("generate JWT ID: %v", err)
+	}
+
+	lifetime := a.Lifetime
+	if lifetime <= 0 {
+		lifetime = defaultClientAssertionLifetime
+	}
+
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss": r.ClientID,
+		"sub": r.ClientID,
+		"aud": r.Endpoint,
+		"iat": now.Unix(),
+		"exp": now.Add(lifetime).Unix(),
+		"jti": jti,
+	}
+
+	assertion, err := signJWT(claims, a.Signer, a.Algorithm, a.KeyID)
+	if err != nil {
+		return fmt.Errorf("sign client assertion: %v", err)
+	}
+
+	r.Form.Set("client_assertion_type", clientAssertionType)
+	r.Form.Set("client_assertion", assertion)
+
+	return nil
+}
+
+// authenticateClient authenticates the client of a token endpoint request and
+// returns the headers to add to the request. If auth is nil, the client
+// secret is sent in the body of the request, if provided.
+func authenticateClient(auth ClientAuthenticator, clientID, clientSecret, endpoint string, form url.Values) (http.Header, error) {
+	header := http.Header{}
+
+	if auth == nil {
+		if clientSecret == "" {
+			return header, nil
+		}
+		auth = &ClientSecretPost{ClientSecret: clientSecret}
+	} else if clientSecret != "" {
+		return nil, fmt.Errorf("client secret and client authenticator are mutually exclusive")
+	}
+
+	if err := auth.AuthenticateClient(&ClientAuthRequest{
+		ClientID: clientID,
+		Endpoint: endpoint,
+		Form:     form,
+		Header:   header,
+	}); err != nil {
+		return nil, fmt.Errorf("authenticate client: %v", err)
+	}
+
+	return header, nil
+}
@@ -0,0 +1,333 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newRSASigner(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	return k
+}
+
+func newECDSASigner(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	return k
+}
+
+func tokenServer(t *testing.T, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		check(r)
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600}`))
+	}))
+}
+
+func TestClientSecretBasic(t *testing.T) {
+	s := tokenServer(t, func(r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok {
+			t.Fatalf("missing basic authentication")
+		}
+		if id != "client-id" {
+			t.Fatalf("invalid client ID: %v", id)
+		}
+		if secret != "s%3Acret" {
+			t.Fatalf("invalid client secret: %v", secret)
+		}
+		if v := r.PostForm.Get("client_secret"); v != "" {
+			t.Fatalf("unexpected client secret in body: %v", v)
+		}
+	})
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.Token(&ims.TokenRequest{
+		Code:       "code",
+		ClientID:   "client-id",
+		ClientAuth: &ims.ClientSecretBasic{ClientSecret: "s:cret"},
+	}); err != nil {
+		t.Fatalf("token: %v", err)
+	}
+}
+
+func TestClientSecretPost(t *testing.T) {
+	s := tokenServer(t, func(r *http.Request) {
+		if v := r.PostForm.Get("client_secret"); v != "client-secret" {
+			t.Fatalf("invalid client secret: %v", v)
+		}
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Fatalf("unexpected basic authentication")
+		}
+	})
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.RefreshToken(&ims.RefreshTokenRequest{
+		RefreshToken: "refresh-token",
+		ClientID:     "client-id",
+		ClientAuth:   &ims.ClientSecretPost{ClientSecret: "client-secret"},
+	}); err != nil {
+		t.Fatalf("refresh token: %v", err)
+	}
+}
+
+func TestPrivateKeyJWT(t *testing.T) {
+	tests := []struct {
+		name      string
+		signer    crypto.Signer
+		algorithm string
+		wantAlg   string
+	}{
+		{
+			name:    "RSA",
+			signer:  newRSASigner(t),
+			wantAlg: "RS256",
+		},
+		{
+			name:      "RSA-PSS",
+			signer:    newRSASigner(t),
+			algorithm: "PS256",
+			wantAlg:   "PS256",
+		},
+		{
+			name:    "ECDSA",
+			signer:  newECDSASigner(t),
+			wantAlg: "ES256",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var endpoint string
+
+			s := tokenServer(t, func(r *http.Request) {
+				if v := r.PostForm.Get("client_secret"); v != "" {
+					t.Fatalf("unexpected client secret: %v", v)
+				}
+				if v := r.PostForm.Get("client_assertion_type"); v != "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" {
+					t.Fatalf("invalid client assertion type: %v", v)
+				}
+
+				token, err := jwt.Parse(r.PostForm.Get("client_assertion"), func(token *jwt.Token) (interface{}, error) {
+					return tt.signer.Public(), nil
+				}, jwt.WithValidMethods([]string{tt.wantAlg}), jwt.WithAudience(endpoint), jwt.WithIssuer("client-id"))
+				if err != nil {
+					t.Fatalf("parse client assertion: %v", err)
+				}
+				if kid := token.Header["kid"]; kid != "key-1" {
+					t.Fatalf("invalid key ID: %v", kid)
+				}
+
+				claims := token.Claims.(jwt.MapClaims)
+
+				if sub, _ := claims.GetSubject(); sub != "client-id" {
+					t.Fatalf("invalid subject: %v", sub)
+				}
+				if jti, _ := claims["jti"].(string); jti == "" {
+					t.Fatalf("missing JWT ID")
+				}
+			})
+			defer s.Close()
+
+			endpoint = s.URL + "/ims/token/v4"
+
+			c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+			if err != nil {
+				t.Fatalf("create client: %v", err)
+			}
+
+			if _, err := c.OBOExchange(&ims.OBOExchangeRequest{
+				ClientID:     "client-id",
+				SubjectToken: "user-token",
+				Scopes:       []string{"openid"},
+				ClientAuth: &ims.PrivateKeyJWT{
+					Signer:    tt.signer,
+					Algorithm: tt.algorithm,
+					KeyID:     "key-1",
+				},
+			}); err != nil {
+				t.Fatalf("exchange: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrivateKeyJWTClusterExchangeAudience(t *testing.T) {
+	signer := newECDSASigner(t)
+
+	var endpoint string
+
+	s := tokenServer(t, func(r *http.Request) {
+		if v := r.URL.Query().Get("client_id"); v != "client-id" {
+			t.Fatalf("invalid client ID: %v", v)
+		}
+
+		if _, err := jwt.Parse(r.PostForm.Get("client_assertion"), func(token *jwt.Token) (interface{}, error) {
+			return signer.Public(), nil
+		}, jwt.WithAudience(endpoint)); err != nil {
+			t.Fatalf("parse client assertion: %v", err)
+		}
+	})
+	defer s.Close()
+
+	endpoint = s.URL + "/ims/token/v3"
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.ClusterExchange(&ims.ClusterExchangeRequest{
+		ClientID:   "client-id",
+		UserToken:  "user-token",
+		UserID:     "user-id",
+		Scopes:     []string{"openid"},
+		ClientAuth: &ims.PrivateKeyJWT{Signer: signer},
+	}); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+}
+
+func TestClientAuthErrors(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		request *ims.TokenRequest
+		wantErr string
+	}{
+		{
+			name: "secret and authenticator",
+			request: &ims.TokenRequest{
+				Code:         "code",
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				ClientAuth:   &ims.ClientSecretBasic{ClientSecret: "client-secret"},
+			},
+			wantErr: "client secret and client authenticator are mutually exclusive",
+		},
+		{
+			name: "empty basic secret",
+			request: &ims.TokenRequest{
+				Code:       "code",
+				ClientID:   "client-id",
+				ClientAuth: &ims.ClientSecretBasic{},
+			},
+			wantErr: "authenticate client: missing client secret",
+		},
+		{
+			name: "missing signer",
+			request: &ims.TokenRequest{
+				Code:       "code",
+				ClientID:   "client-id",
+				ClientAuth: &ims.PrivateKeyJWT{},
+			},
+			wantErr: "authenticate client: sign client assertion: missing signer",
+		},
+		{
+			name: "algorithm not matching key",
+			request: &ims.TokenRequest{
+				Code:       "code",
+				ClientID:   "client-id",
+				ClientAuth: &ims.PrivateKeyJWT{Signer: newRSASigner(t), Algorithm: "ES256"},
+			},
+			wantErr: "authenticate client: sign client assertion: signing algorithm ES256 requires an ECDSA key",
+		},
+		{
+			name: "unsupported algorithm",
+			request: &ims.TokenRequest{
+				Code:       "code",
+				ClientID:   "client-id",
+				ClientAuth: &ims.PrivateKeyJWT{Signer: newRSASigner(t), Algorithm: "HS256"},
+			},
+			wantErr: `authenticate client: sign client assertion: unsupported signing algorithm: "HS256"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Token(tt.request); err == nil {
+				t.Fatalf("expected error")
+			} else if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientSecretBasicEncoding(t *testing.T) {
+	r := &ims.ClientAuthRequest{
+		ClientID: "client id",
+		Form:     url.Values{},
+		Header:   http.Header{},
+	}
+
+	if err := (&ims.ClientSecretBasic{ClientSecret: "a+b"}).AuthenticateClient(r); err != nil {
+		t.Fatalf("authenticate client: %v", err)
+	}
+
+	if h := r.Header.Get("Authorization"); !strings.HasPrefix(h, "Basic ") {
+		t.Fatalf("invalid authorization header: %v", h)
+	}
+
+	req := &http.Request{Header: r.Header}
+
+	id, secret, _ := req.BasicAuth()
+	if id != "client+id" {
+		t.Fatalf("invalid client ID: %v", id)
+	}
+	if secret != "a%2Bb" {
+		t.Fatalf("invalid client secret: %v", secret)
+	}
+}
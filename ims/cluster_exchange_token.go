@@ -32,6 +32,9 @@ type ClusterExchangeRequest struct {
 	UserID       string
 	OrgID        string
 	Resource     []string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ClientAuthenticator
 }
 
 // ClusterExchangeResponse is the response for ClusterExchange.
@@ -47,7 +50,6 @@ func (c *Client) ClusterExchangeWithContext(ctx context.Context, r *ClusterExcha
 
 	data := url.Values{}
 	data.Set("grant_type", "cluster_at_exchange")
-	data.Set("client_secret", r.ClientSecret)
 	data.Set("user_token", r.UserToken)
 	switch {
 	case r.UserID != "":
@@ -66,13 +68,21 @@ func (c *Client) ClusterExchangeWithContext(ctx context.Context, r *ClusterExcha
 		data.Add("resource", res)
 	}
 
+	tokenURL := fmt.Sprintf("%s/ims/token/v3", c.url)
+
+	header, err := authenticateClient(r.ClientAuth, r.ClientID, r.ClientSecret, tokenURL, data)
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		fmt.Sprintf("%s/ims/token/v3?client_id=%s", c.url, r.ClientID),
+		fmt.Sprintf("%s?client_id=%s", tokenURL, r.ClientID),
 		strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %v", err)
 	}
 
+	req.Header = header
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	res, err := c.do(req)
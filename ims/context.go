@@ -0,0 +1,31 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import "context"
+
+type contextKey string
+
+var contextKeyAccessToken = contextKey("access-token")
+
+// WithAccessToken returns a copy of the context carrying the access token. It
+// is typically called by the handler receiving the token of a user, so that
+// OBOTransport can exchange it when calling downstream services.
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, contextKeyAccessToken, token)
+}
+
+// AccessTokenFromContext returns the access token stored in the context by
+// WithAccessToken, if any.
+func AccessTokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(contextKeyAccessToken).(string)
+	return token, ok && token != ""
+}
@@ -15,18 +15,114 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 )
 
+// DCRRequest is the request for DCR. It contains the metadata of the client
+// to register (RFC 7591, section 2).
 type DCRRequest struct {
 	ClientName   string
 	RedirectURIs []string
 	Scopes       []string
+	// GrantTypes is the list of grant types the client will use, e.g.
+	// authorization_code. Optional.
+	GrantTypes []string
+	// ResponseTypes is the list of response types the client will use, e.g.
+	// code. Optional.
+	ResponseTypes []string
+	// TokenEndpointAuthMethod is how the client authenticates to the token
+	// endpoint, e.g. client_secret_post or none. Optional.
+	TokenEndpointAuthMethod string
+	// ClientURI is the URL of the home page of the client. Optional.
+	ClientURI string
+	// LogoURI is the URL of the logo of the client. Optional.
+	LogoURI string
+	// PolicyURI is the URL of the privacy policy of the client. Optional.
+	PolicyURI string
+	// TOSURI is the URL of the terms of service of the client. Optional.
+	TOSURI string
+	// Contacts is the list of the email addresses of the people responsible
+	// for the client. Optional.
+	Contacts []string
+	// SoftwareStatement is a signed JWT asserting the metadata of the client.
+	// Optional.
+	SoftwareStatement string
+	// RedirectURIPolicy is the policy used to validate RedirectURIs before
+	// sending the request. It isn't part of the metadata of the client. If not
+	// provided, the zero policy is used.
+	RedirectURIPolicy *RedirectURIPolicy
 }
 
+// DCRResponse is the response for DCR, GetDCR and UpdateDCR. Besides the
+// credentials of the client, it contains the metadata registered by IMS,
+// which might differ from the requested one. If the body of the response is
+// empty, only the raw Response is set.
 type DCRResponse struct {
 	Response
+	ClientID     string
+	ClientSecret string
+	// ClientIDIssuedAt is when the client ID was issued. It is the zero time
+	// if not returned.
+	ClientIDIssuedAt time.Time
+	// ClientSecretExpiresAt is when the client secret expires. It is the zero
+	// time if the secret doesn't expire.
+	ClientSecretExpiresAt time.Time
+	// RegistrationAccessToken is the token to use with GetDCR, UpdateDCR and
+	// DeleteDCR.
+	RegistrationAccessToken string
+	// RegistrationClientURI is the URL of the client configuration endpoint,
+	// to use with GetDCR, UpdateDCR and DeleteDCR.
+	RegistrationClientURI string
+
+	ClientName              string
+	RedirectURIs            []string
+	Scopes                  []string
+	GrantTypes              []string
+	ResponseTypes           []string
+	TokenEndpointAuthMethod string
+	ClientURI               string
+	LogoURI                 string
+	PolicyURI               string
+	TOSURI                  string
+	Contacts                []string
+	SoftwareStatement       string
+}
+
+// dcrMetadata is the JSON representation of the client metadata.
+type dcrMetadata struct {
+	ClientName              string   `json:"client_name,omitempty"`
+	RedirectURIs            []string `json:"redirect_uris,omitempty"`
+	Scope                   string   `json:"scope,omitempty"`
+	GrantTypes              []string `json:"grant_types,omitempty"`
+	ResponseTypes           []string `json:"response_types,omitempty"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method,omitempty"`
+	ClientURI               string   `json:"client_uri,omitempty"`
+	LogoURI                 string   `json:"logo_uri,omitempty"`
+	PolicyURI               string   `json:"policy_uri,omitempty"`
+	TOSURI                  string   `json:"tos_uri,omitempty"`
+	Contacts                []string `json:"contacts,omitempty"`
+	SoftwareStatement       string   `json:"software_statement,omitempty"`
+}
+
+func (r *DCRRequest) metadata() dcrMetadata {
+	return dcrMetadata{
+		ClientName:              r.ClientName,
+		RedirectURIs:            r.RedirectURIs,
+		Scope:                   strings.Join(r.Scopes, " "),
+		GrantTypes:              r.GrantTypes,
+		ResponseTypes:           r.ResponseTypes,
+		TokenEndpointAuthMethod: r.TokenEndpointAuthMethod,
+		ClientURI:               r.ClientURI,
+		LogoURI:                 r.LogoURI,
+		PolicyURI:               r.PolicyURI,
+		TOSURI:                  r.TOSURI,
+		Contacts:                r.Contacts,
+		SoftwareStatement:       r.SoftwareStatement,
+	}
 }
 
 func (c *Client) validateDCRRequest(r *DCRRequest) error {
@@ -35,25 +131,24 @@ func (c *Client) validateDCRRequest(r *DCRRequest) error {
 		return fmt.Errorf("missing client name parameter")
 	case len(r.RedirectURIs) == 0:
 		return fmt.Errorf("missing redirect URIs parameter")
-	default:
-		return nil
 	}
+
+	for _, uri := range r.RedirectURIs {
+		if err := ValidateRedirectURI(uri, r.RedirectURIPolicy); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+// DCRWithContext registers a new client (RFC 7591).
 func (c *Client) DCRWithContext(ctx context.Context, r *DCRRequest) (*DCRResponse, error) {
 	if err := c.validateDCRRequest(r); err != nil {
 		return nil, fmt.Errorf("invalid parameters for client registration: %v", err)
 	}
 
-	payload, err := json.Marshal(struct {
-		ClientName   string   `json:"client_name"`
-		RedirectURIs []string `json:"redirect_uris"`
-		Scope        string   `json:"scope,omitempty"`
-	}{
-		ClientName:   r.ClientName,
-		RedirectURIs: r.RedirectURIs,
-		Scope:        strings.Join(r.Scopes, " "),
-	})
+	payload, err := json.Marshal(r.metadata())
 	if err != nil {
 		return nil, fmt.Errorf("error building registration payload: %v", err)
 	}
@@ -74,9 +169,247 @@ func (c *Client) DCRWithContext(ctx context.Context, r *DCRRequest) (*DCRRespons
 		return nil, errorResponse(res)
 	}
 
-	return &DCRResponse{Response: *res}, nil
+	return dcrResponse(res)
 }
 
+// DCR is equivalent to DCRWithContext with a background context.
 func (c *Client) DCR(r *DCRRequest) (*DCRResponse, error) {
 	return c.DCRWithContext(context.Background(), r)
 }
+
+// GetDCRRequest is the request for GetDCR.
+type GetDCRRequest struct {
+	// RegistrationClientURI is the URL of the client configuration endpoint,
+	// as returned in DCRResponse. This field is required.
+	RegistrationClientURI string
+	// RegistrationAccessToken is the token returned in DCRResponse. This
+	// field is required.
+	RegistrationAccessToken string
+}
+
+// GetDCRWithContext reads the current configuration of a registered client
+// (RFC 7592, section 2.1).
+func (c *Client) GetDCRWithContext(ctx context.Context, r *GetDCRRequest) (*DCRResponse, error) {
+	res, err := c.doDCRManagement(ctx, http.MethodGet, r.RegistrationClientURI, r.RegistrationAccessToken, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	return dcrResponse(res)
+}
+
+// GetDCR is equivalent to GetDCRWithContext with a background context.
+func (c *Client) GetDCR(r *GetDCRRequest) (*DCRResponse, error) {
+	return c.GetDCRWithContext(context.Background(), r)
+}
+
+// UpdateDCRRequest is the request for UpdateDCR.
+type UpdateDCRRequest struct {
+	// DCRRequest is the new metadata of the client. It replaces the current
+	// metadata: omitted fields might be reset by IMS.
+	DCRRequest
+	// RegistrationClientURI is the URL of the client configuration endpoint,
+	// as returned in DCRResponse. This field is required.
+	RegistrationClientURI string
+	// RegistrationAccessToken is the token returned in DCRResponse. This
+	// field is required.
+	RegistrationAccessToken string
+	// ClientID is the ID of the client. This field is required.
+	ClientID string
+	// ClientSecret is the current secret of the client. Optional.
+	ClientSecret string
+}
+
+// UpdateDCRWithContext replaces the metadata of a registered client (RFC 7592,
+// section 2.2).
+func (c *Client) UpdateDCRWithContext(ctx context.Context, r *UpdateDCRRequest) (*DCRResponse, error) {
+	if r.ClientID == "" {
+		return nil, fmt.Errorf("invalid parameters for client update: missing client ID parameter")
+	}
+
+	if err := c.validateDCRRequest(&r.DCRRequest); err != nil {
+		return nil, fmt.Errorf("invalid parameters for client update: %v", err)
+	}
+
+	payload, err := json.Marshal(struct {
+		dcrMetadata
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret,omitempty"`
+	}{
+		dcrMetadata:  r.metadata(),
+		ClientID:     r.ClientID,
+		ClientSecret: r.ClientSecret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building registration payload: %v", err)
+	}
+
+	res, err := c.doDCRManagement(ctx, http.MethodPut, r.RegistrationClientURI, r.RegistrationAccessToken, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	return dcrResponse(res)
+}
+
+// UpdateDCR is equivalent to UpdateDCRWithContext with a background context.
+func (c *Client) UpdateDCR(r *UpdateDCRRequest) (*DCRResponse, error) {
+	return c.UpdateDCRWithContext(context.Background(), r)
+}
+
+// DeleteDCRRequest is the request for DeleteDCR.
+type DeleteDCRRequest struct {
+	// RegistrationClientURI is the URL of the client configuration endpoint,
+	// as returned in DCRResponse. This field is required.
+	RegistrationClientURI string
+	// RegistrationAccessToken is the token returned in DCRResponse. This
+	// field is required.
+	RegistrationAccessToken string
+}
+
+// DeleteDCRResponse is the response for DeleteDCR.
+type DeleteDCRResponse struct {
+	Response
+}
+
+// DeleteDCRWithContext deprovisions a registered client (RFC 7592, section
+// 2.3).
+func (c *Client) DeleteDCRWithContext(ctx context.Context, r *DeleteDCRRequest) (*DeleteDCRResponse, error) {
+	res, err := c.doDCRManagement(ctx, http.MethodDelete, r.RegistrationClientURI, r.RegistrationAccessToken, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	return &DeleteDCRResponse{
+		Response: *res,
+	}, nil
+}
+
+// DeleteDCR is equivalent to DeleteDCRWithContext with a background context.
+func (c *Client) DeleteDCR(r *DeleteDCRRequest) (*DeleteDCRResponse, error) {
+	return c.DeleteDCRWithContext(context.Background(), r)
+}
+
+// doDCRManagement performs a request to the client configuration endpoint. The
+// endpoint must be on the same host as the IMS URL, so that the registration
+// access token is never sent elsewhere.
+func (c *Client) doDCRManagement(ctx context.Context, method, registrationClientURI, registrationAccessToken string, payload []byte) (*Response, error) {
+	if registrationClientURI == "" {
+		return nil, fmt.Errorf("missing registration client URI")
+	}
+
+	if registrationAccessToken == "" {
+		return nil, fmt.Errorf("missing registration access token")
+	}
+
+	if err := c.validateRegistrationClientURI(registrationClientURI); err != nil {
+		return nil, fmt.Errorf("invalid registration client URI: %v", err)
+	}
+
+	var body io.Reader
+
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, registrationClientURI, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", registrationAccessToken))
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request: %v", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) validateRegistrationClientURI(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+
+	base, err := url.Parse(c.url)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != base.Scheme || u.Host != base.Host {
+		return fmt.Errorf("not on the IMS host: %v", s)
+	}
+
+	return nil
+}
+
+func dcrResponse(res *Response) (*DCRResponse, error) {
+	var body struct {
+		dcrMetadata
+		ClientID                string `json:"client_id"`
+		ClientSecret            string `json:"client_secret"`
+		ClientIDIssuedAt        int64  `json:"client_id_issued_at"`
+		ClientSecretExpiresAt   int64  `json:"client_secret_expires_at"`
+		RegistrationAccessToken string `json:"registration_access_token"`
+		RegistrationClientURI   string `json:"registration_client_uri"`
+	}
+
+	// Successful responses without a body are accepted as they are.
+	if len(bytes.TrimSpace(res.Body)) == 0 {
+		return &DCRResponse{Response: *res}, nil
+	}
+
+	if err := json.Unmarshal(res.Body, &body); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return &DCRResponse{
+		Response:                *res,
+		ClientID:                body.ClientID,
+		ClientSecret:            body.ClientSecret,
+		ClientIDIssuedAt:        unixTime(body.ClientIDIssuedAt),
+		ClientSecretExpiresAt:   unixTime(body.ClientSecretExpiresAt),
+		RegistrationAccessToken: body.RegistrationAccessToken,
+		RegistrationClientURI:   body.RegistrationClientURI,
+		ClientName:              body.ClientName,
+		RedirectURIs:            body.RedirectURIs,
+		Scopes:                  strings.Fields(body.Scope),
+		GrantTypes:              body.GrantTypes,
+		ResponseTypes:           body.ResponseTypes,
+		TokenEndpointAuthMethod: body.TokenEndpointAuthMethod,
+		ClientURI:               body.ClientURI,
+		LogoURI:                 body.LogoURI,
+		PolicyURI:               body.PolicyURI,
+		TOSURI:                  body.TOSURI,
+		Contacts:                body.Contacts,
+		SoftwareStatement:       body.SoftwareStatement,
+	}, nil
+}
+
+// unixTime converts seconds since the epoch to a time, mapping 0 to the zero
+// time.
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, 0)
+}
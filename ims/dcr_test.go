@@ -15,7 +15,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -126,6 +128,82 @@ func TestDCR2xxRange(t *testing.T) {
 	}
 }
 
+func TestDCRWithoutMetadata(t *testing.T) {
+	tests := []struct {
+		body    string
+		wantErr bool
+	}{
+		{body: ""},
+		{body: " \n"},
+		{body: "Created", wantErr: true},
+		{body: `{"client_id":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+
+		c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+		if err != nil {
+			t.Fatalf("create client: %v", err)
+		}
+
+		resp, err := c.DCR(&ims.DCRRequest{
+			ClientName:   "my-app",
+			RedirectURIs: []string{"https://example.com/callback"},
+		})
+
+		s.Close()
+
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("expected error for body %q", tt.body)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("expected success for body %q: %v", tt.body, err)
+		}
+		if resp.StatusCode != http.StatusCreated || string(resp.Body) != tt.body || resp.ClientID != "" {
+			t.Fatalf("invalid response for body %q: %+v", tt.body, resp)
+		}
+	}
+}
+
+func TestDCRLocalhostRedirectURI(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"client_id":"native-client"}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	resp, err := c.DCR(&ims.DCRRequest{
+		ClientName:   "my-app",
+		RedirectURIs: []string{"http://localhost:8000/callback"},
+	})
+	if err != nil {
+		t.Fatalf("register client: %v", err)
+	}
+	if resp.ClientID != "native-client" {
+		t.Fatalf("invalid client ID: %v", resp.ClientID)
+	}
+
+	if _, err := c.DCR(&ims.DCRRequest{
+		ClientName:        "my-app",
+		RedirectURIs:      []string{"http://localhost:8000/callback"},
+		RedirectURIPolicy: &ims.RedirectURIPolicy{RequireLoopbackIP: true},
+	}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
 func TestDCRError(t *testing.T) {
 	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -241,6 +319,11 @@ func TestDCRInvalidRequest(t *testing.T) {
 			request: &ims.DCRRequest{ClientName: "my-app", RedirectURIs: []string{}},
 			wantErr: "invalid parameters for client registration: missing redirect URIs parameter",
 		},
+		{
+			name:    "insecure RedirectURI",
+			request: &ims.DCRRequest{ClientName: "my-app", RedirectURIs: []string{"https://example.com/cb", "http://example.com/cb"}},
+			wantErr: `invalid parameters for client registration: invalid redirect URI "http://example.com/cb": HTTPS required for non-loopback host example.com`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -365,3 +448,218 @@ func TestDCRWithoutScopes(t *testing.T) {
 		t.Fatalf("invalid body: %v", string(resp.Body))
 	}
 }
+
+func TestDCRMetadata(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+
+		want := map[string]interface{}{
+			"client_name":                "my-app",
+			"redirect_uris":              []interface{}{"https://example.com/callback"},
+			"grant_types":                []interface{}{"authorization_code", "refresh_token"},
+			"response_types":             []interface{}{"code"},
+			"token_endpoint_auth_method": "client_secret_basic",
+			"client_uri":                 "https://example.com",
+			"logo_uri":                   "https://example.com/logo.png",
+			"policy_uri":                 "https://example.com/privacy",
+			"tos_uri":                    "https://example.com/tos",
+			"contacts":                   []interface{}{"admin@example.com"},
+			"software_statement":         "software-statement",
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Fatalf("invalid request body: %v", body)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{
+			"client_id": "client-id",
+			"client_secret": "client-secret",
+			"client_id_issued_at": 1700000000,
+			"client_secret_expires_at": 0,
+			"registration_access_token": "registration-token",
+			"registration_client_uri": "` + "http://" + r.Host + `/ims/register/client-id",
+			"client_name": "my-app",
+			"redirect_uris": ["https://example.com/callback"],
+			"scope": "openid profile",
+			"grant_types": ["authorization_code"],
+			"token_endpoint_auth_method": "client_secret_basic"
+		}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	res, err := c.DCR(&ims.DCRRequest{
+		ClientName:              "my-app",
+		RedirectURIs:            []string{"https://example.com/callback"},
+		GrantTypes:              []string{"authorization_code", "refresh_token"},
+		ResponseTypes:           []string{"code"},
+		TokenEndpointAuthMethod: "client_secret_basic",
+		ClientURI:               "https://example.com",
+		LogoURI:                 "https://example.com/logo.png",
+		PolicyURI:               "https://example.com/privacy",
+		TOSURI:                  "https://example.com/tos",
+		Contacts:                []string{"admin@example.com"},
+		SoftwareStatement:       "software-statement",
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if res.ClientID != "client-id" || res.ClientSecret != "client-secret" {
+		t.Fatalf("invalid credentials: %v, %v", res.ClientID, res.ClientSecret)
+	}
+	if !res.ClientIDIssuedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("invalid client ID issued at: %v", res.ClientIDIssuedAt)
+	}
+	if !res.ClientSecretExpiresAt.IsZero() {
+		t.Fatalf("invalid client secret expiration: %v", res.ClientSecretExpiresAt)
+	}
+	if res.RegistrationAccessToken != "registration-token" {
+		t.Fatalf("invalid registration access token: %v", res.RegistrationAccessToken)
+	}
+	if res.RegistrationClientURI != s.URL+"/ims/register/client-id" {
+		t.Fatalf("invalid registration client URI: %v", res.RegistrationClientURI)
+	}
+	if !reflect.DeepEqual(res.Scopes, []string{"openid", "profile"}) {
+		t.Fatalf("invalid scopes: %v", res.Scopes)
+	}
+	if !reflect.DeepEqual(res.GrantTypes, []string{"authorization_code"}) {
+		t.Fatalf("invalid grant types: %v", res.GrantTypes)
+	}
+	if res.TokenEndpointAuthMethod != "client_secret_basic" {
+		t.Fatalf("invalid token endpoint auth method: %v", res.TokenEndpointAuthMethod)
+	}
+}
+
+func TestDCRManagement(t *testing.T) {
+	var deleted bool
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ims/register/client-id" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+
+		if v := r.Header.Get("Authorization"); v != "Bearer registration-token" {
+			t.Fatalf("invalid authorization: %v", v)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"client_id": "client-id", "client_name": "my-app"}`))
+		case http.MethodPut:
+			var body struct {
+				ClientID     string   `json:"client_id"`
+				ClientSecret string   `json:"client_secret"`
+				ClientName   string   `json:"client_name"`
+				RedirectURIs []string `json:"redirect_uris"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode request body: %v", err)
+			}
+			if body.ClientID != "client-id" || body.ClientSecret != "client-secret" {
+				t.Fatalf("invalid credentials: %v, %v", body.ClientID, body.ClientSecret)
+			}
+			_, _ = w.Write([]byte(`{"client_id": "client-id", "client_name": "` + body.ClientName + `"}`))
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("invalid method: %v", r.Method)
+		}
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	uri := s.URL + "/ims/register/client-id"
+
+	got, err := c.GetDCR(&ims.GetDCRRequest{
+		RegistrationClientURI:   uri,
+		RegistrationAccessToken: "registration-token",
+	})
+	if err != nil {
+		t.Fatalf("get registration: %v", err)
+	}
+	if got.ClientName != "my-app" {
+		t.Fatalf("invalid client name: %v", got.ClientName)
+	}
+
+	updated, err := c.UpdateDCR(&ims.UpdateDCRRequest{
+		DCRRequest: ims.DCRRequest{
+			ClientName:   "my-renamed-app",
+			RedirectURIs: []string{"https://example.com/callback"},
+		},
+		RegistrationClientURI:   uri,
+		RegistrationAccessToken: "registration-token",
+		ClientID:                "client-id",
+		ClientSecret:            "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("update registration: %v", err)
+	}
+	if updated.ClientName != "my-renamed-app" {
+		t.Fatalf("invalid client name: %v", updated.ClientName)
+	}
+
+	if _, err := c.DeleteDCR(&ims.DeleteDCRRequest{
+		RegistrationClientURI:   uri,
+		RegistrationAccessToken: "registration-token",
+	}); err != nil {
+		t.Fatalf("delete registration: %v", err)
+	}
+	if !deleted {
+		t.Fatalf("registration not deleted")
+	}
+}
+
+func TestDCRManagementInvalidRequest(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "https://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		request *ims.GetDCRRequest
+		wantErr string
+	}{
+		{
+			name:    "missing URI",
+			request: &ims.GetDCRRequest{RegistrationAccessToken: "token"},
+			wantErr: "missing registration client URI",
+		},
+		{
+			name:    "missing token",
+			request: &ims.GetDCRRequest{RegistrationClientURI: "https://ims.endpoint/ims/register/id"},
+			wantErr: "missing registration access token",
+		},
+		{
+			name: "foreign host",
+			request: &ims.GetDCRRequest{
+				RegistrationClientURI:   "https://attacker.example.com/ims/register/id",
+				RegistrationAccessToken: "token",
+			},
+			wantErr: "invalid registration client URI: not on the IMS host: https://attacker.example.com/ims/register/id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.GetDCR(tt.request); err == nil {
+				t.Fatalf("expected error")
+			} else if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+const (
+	// defaultDevicePollInterval is the polling interval used when the server
+	// doesn't return one, as mandated by RFC 8628, section 3.2.
+	defaultDevicePollInterval = 5 * time.Second
+	// devicePollSlowDown is added to the polling interval every time the
+	// server answers with slow_down, as mandated by RFC 8628, section 3.5.
+	devicePollSlowDown = 5 * time.Second
+)
+
+// DeviceCodeRequest is the request for starting a device authorization grant
+// (RFC 8628).
+type DeviceCodeRequest struct {
+	// ClientID is the client ID. This field is required.
+	ClientID string
+	// ClientSecret is the client secret. This field is optional, since the
+	// device flow is usually performed by public clients.
+	ClientSecret string
+	// Scope is the list of scopes to request. This field is required.
+	Scope []string
+	// Resources provided to be added as access token audiences
+	Resource []string
+}
+
+// DeviceCodeResponse is the response of a device authorization request. The
+// UserCode and the VerificationURI must be displayed to the user, while the
+// DeviceCode is used to poll for the access token.
+type DeviceCodeResponse struct {
+	Response
+	// DeviceCode is the code used by the device to poll for the token.
+	DeviceCode string
+	// UserCode is the code the user must enter at the verification URI.
+	UserCode string
+	// VerificationURI is the URI the user must visit to authorize the device.
+	VerificationURI string
+	// VerificationURIComplete is the verification URI including the user code,
+	// if returned by the server. It is suitable for non-textual transmission,
+	// e.g. a QR code.
+	VerificationURIComplete string
+	// ExpiresIn is the lifetime of the device code and of the user code.
+	ExpiresIn time.Duration
+	// Interval is the minimum amount of time to wait between polling
+	// requests.
+	Interval time.Duration
+}
+
+// DeviceCodeWithContext starts a device authorization grant by requesting a
+// device code and a user code.
+func (c *Client) DeviceCodeWithContext(ctx context.Context, r *DeviceCodeRequest) (*DeviceCodeResponse, error) {
+	if r.ClientID == "" {
+		return nil, fmt.Errorf("missing client ID")
+	}
+
+	if len(r.Scope) == 0 {
+		return nil, fmt.Errorf("missing scope")
+	}
+
+	data := url.Values{}
+
+	data.Set("client_id", r.ClientID)
+	data.Set("scope", strings.Join(r.Scope, ","))
+
+	if r.ClientSecret != "" {
+		data.Set("client_secret", r.ClientSecret)
+	}
+
+	for _, res := range r.Resource {
+		data.Add("resource", res)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/ims/device/code/v1", c.url), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("perform request: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	var payload struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+
+	if err := json.Unmarshal(res.Body, &payload); err != nil {
+		return nil, fmt.Errorf("decode response: %v", err)
+	}
+
+	if payload.DeviceCode == "" {
+		return nil, fmt.Errorf("missing device code in response")
+	}
+
+	interval := time.Second * time.Duration(payload.Interval)
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+
+	return &DeviceCodeResponse{
+		Response:                *res,
+		DeviceCode:              payload.DeviceCode,
+		UserCode:                payload.UserCode,
+		VerificationURI:         payload.VerificationURI,
+		VerificationURIComplete: payload.VerificationURIComplete,
+		ExpiresIn:               time.Second * time.Duration(payload.ExpiresIn),
+		Interval:                interval,
+	}, nil
+}
+
+// DeviceCode is equivalent to DeviceCodeWithContext with a background context.
+func (c *Client) DeviceCode(r *DeviceCodeRequest) (*DeviceCodeResponse, error) {
+	return c.DeviceCodeWithContext(context.Background(), r)
+}
+
+// DeviceTokenRequest is the request for polling the access token of a device
+// authorization grant.
+type DeviceTokenRequest struct {
+	// DeviceCode is the device code returned by DeviceCode. This field is
+	// required.
+	DeviceCode string
+	// ClientID is the client ID. This field is required.
+	ClientID string
+	// ClientSecret is the client secret. This field is optional.
+	ClientSecret string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ClientAuthenticator
+	// Interval is the amount of time to wait between polling requests. It
+	// should be set to the interval returned by DeviceCode. If not provided,
+	// it defaults to five seconds.
+	Interval time.Duration
+}
+
+// DeviceTokenWithContext polls the token endpoint until the user authorizes
+// the device, the user denies the authorization, or the device code expires.
+// Polling honors the authorization_pending and slow_down error codes, and
+// stops as soon as the context is cancelled. Every other error returned by
+// the server terminates the polling and is returned as an Error.
+func (c *Client) DeviceTokenWithContext(ctx context.Context, r *DeviceTokenRequest) (*TokenResponse, error) {
+	if r.DeviceCode == "" {
+		return nil, fmt.Errorf("missing device code")
+	}
+
+	if r.ClientID == "" {
+		return nil, fmt.Errorf("missing client ID")
+	}
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		res, err := c.pollDeviceToken(ctx, r)
+		if err == nil {
+			return res, nil
+		}
+
+		imsErr, ok := IsError(err)
+		if !ok {
+			return nil, err
+		}
+
+		switch imsErr.ErrorCode {
+		case "authorization_pending":
+			// The user hasn't completed the authorization yet.
+		case "slow_down":
+			interval += devicePollSlowDown
+		default:
+			return nil, err
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+// DeviceToken is equivalent to DeviceTokenWithContext with a background
+// context.
+func (c *Client) DeviceToken(r *DeviceTokenRequest) (*TokenResponse, error) {
+	return c.DeviceTokenWithContext(context.Background(), r)
+}
+
+func (c *Client) pollDeviceToken(ctx context.Context, r *DeviceTokenRequest) (*TokenResponse, error) {
+	data := url.Values{}
+
+	data.Set("grant_type", deviceCodeGrantType)
+	data.Set("device_code", r.DeviceCode)
+	data.Set("client_id", r.ClientID)
+
+	tokenURL := fmt.Sprintf("%s/ims/token/v4", c.url)
+
+	// Assertions are single-use, so the client is authenticated again for
+	// every polling request.
+	header, err := authenticateClient(r.ClientAuth, r.ClientID, r.ClientSecret, tokenURL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %v", err)
+	}
+
+	req.Header = header
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("perform request: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		UserID       string `json:"userId"`
+	}
+
+	if err := json.Unmarshal(res.Body, &payload); err != nil {
+		return nil, fmt.Errorf("decode response: %v", err)
+	}
+
+	return &TokenResponse{
+		Response:     *res,
+		AccessToken:  payload.AccessToken,
+		RefreshToken: payload.RefreshToken,
+		ExpiresIn:    time.Second * time.Duration(payload.ExpiresIn),
+		UserID:       payload.UserID,
+	}, nil
+}
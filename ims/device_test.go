@@ -0,0 +1,331 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func writeDeviceError(t *testing.T, w http.ResponseWriter, code string) {
+	t.Helper()
+
+	w.WriteHeader(http.StatusBadRequest)
+
+	body := struct {
+		ErrorCode string `json:"error"`
+	}{
+		ErrorCode: code,
+	}
+
+	if err := json.NewEncoder(w).Encode(&body); err != nil {
+		t.Fatalf("encode response: %v", err)
+	}
+}
+
+func TestDeviceCode(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("invalid method: %v", r.Method)
+		}
+		if r.URL.Path != "/ims/device/code/v1" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if v := r.PostForm.Get("client_id"); v != "client-id" {
+			t.Fatalf("invalid client ID: %v", v)
+		}
+		if v := r.PostForm.Get("scope"); v != "openid,AdobeID" {
+			t.Fatalf("invalid scope: %v", v)
+		}
+		if v := r.PostForm.Get("client_secret"); v != "" {
+			t.Fatalf("unexpected client secret: %v", v)
+		}
+
+		_, _ = w.Write([]byte(`{
+			"device_code": "device-code",
+			"user_code": "ABCD-EFGH",
+			"verification_uri": "https://ims.example.com/device",
+			"verification_uri_complete": "https://ims.example.com/device?user_code=ABCD-EFGH",
+			"expires_in": 600,
+			"interval": 3
+		}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	r, err := c.DeviceCode(&ims.DeviceCodeRequest{
+		ClientID: "client-id",
+		Scope:    []string{"openid", "AdobeID"},
+	})
+	if err != nil {
+		t.Fatalf("device code: %v", err)
+	}
+	if r.DeviceCode != "device-code" {
+		t.Fatalf("invalid device code: %v", r.DeviceCode)
+	}
+	if r.UserCode != "ABCD-EFGH" {
+		t.Fatalf("invalid user code: %v", r.UserCode)
+	}
+	if r.VerificationURI != "https://ims.example.com/device" {
+		t.Fatalf("invalid verification URI: %v", r.VerificationURI)
+	}
+	if r.VerificationURIComplete != "https://ims.example.com/device?user_code=ABCD-EFGH" {
+		t.Fatalf("invalid complete verification URI: %v", r.VerificationURIComplete)
+	}
+	if r.ExpiresIn != 600*time.Second {
+		t.Fatalf("invalid expiration: %v", r.ExpiresIn)
+	}
+	if r.Interval != 3*time.Second {
+		t.Fatalf("invalid interval: %v", r.Interval)
+	}
+}
+
+func TestDeviceCodeDefaultInterval(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"device_code": "device-code", "user_code": "user-code"}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	r, err := c.DeviceCode(&ims.DeviceCodeRequest{
+		ClientID: "client-id",
+		Scope:    []string{"openid"},
+	})
+	if err != nil {
+		t.Fatalf("device code: %v", err)
+	}
+	if r.Interval != 5*time.Second {
+		t.Fatalf("invalid interval: %v", r.Interval)
+	}
+}
+
+func TestDeviceCodeError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeDeviceError(t, w, "invalid_client")
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = c.DeviceCode(&ims.DeviceCodeRequest{
+		ClientID: "client-id",
+		Scope:    []string{"openid"},
+	})
+
+	imsErr, ok := ims.IsError(err)
+	if !ok {
+		t.Fatalf("expected IMS error")
+	}
+	if imsErr.ErrorCode != "invalid_client" {
+		t.Fatalf("invalid error code: %v", imsErr.ErrorCode)
+	}
+}
+
+func TestDeviceCodeInvalidRequest(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.DeviceCode(&ims.DeviceCodeRequest{
+		Scope: []string{"openid"},
+	}); err == nil || err.Error() != "missing client ID" {
+		t.Fatalf("invalid error: %v", err)
+	}
+
+	if _, err := c.DeviceCode(&ims.DeviceCodeRequest{
+		ClientID: "client-id",
+	}); err == nil || err.Error() != "missing scope" {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestDeviceToken(t *testing.T) {
+	var calls int32
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ims/token/v4" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if v := r.PostForm.Get("grant_type"); v != "urn:ietf:params:oauth:grant-type:device_code" {
+			t.Fatalf("invalid grant type: %v", v)
+		}
+		if v := r.PostForm.Get("device_code"); v != "device-code" {
+			t.Fatalf("invalid device code: %v", v)
+		}
+		if v := r.PostForm.Get("client_id"); v != "client-id" {
+			t.Fatalf("invalid client ID: %v", v)
+		}
+
+		if atomic.AddInt32(&calls, 1) < 3 {
+			writeDeviceError(t, w, "authorization_pending")
+			return
+		}
+
+		_, _ = w.Write([]byte(`{
+			"access_token": "access-token",
+			"refresh_token": "refresh-token",
+			"expires_in": 3600,
+			"userId": "user-id"
+		}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	r, err := c.DeviceToken(&ims.DeviceTokenRequest{
+		DeviceCode: "device-code",
+		ClientID:   "client-id",
+		Interval:   time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("device token: %v", err)
+	}
+	if r.AccessToken != "access-token" {
+		t.Fatalf("invalid access token: %v", r.AccessToken)
+	}
+	if r.RefreshToken != "refresh-token" {
+		t.Fatalf("invalid refresh token: %v", r.RefreshToken)
+	}
+	if r.ExpiresIn != 3600*time.Second {
+		t.Fatalf("invalid expiration: %v", r.ExpiresIn)
+	}
+	if r.UserID != "user-id" {
+		t.Fatalf("invalid user ID: %v", r.UserID)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("invalid number of polling requests: %v", n)
+	}
+}
+
+func TestDeviceTokenSlowDown(t *testing.T) {
+	var calls int32
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeDeviceError(t, w, "slow_down")
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// After slow_down, the interval grows by five seconds. The context expires
+	// long before the second polling request is sent.
+
+	_, err = c.DeviceTokenWithContext(ctx, &ims.DeviceTokenRequest{
+		DeviceCode: "device-code",
+		ClientID:   "client-id",
+		Interval:   time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("invalid error: %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("invalid number of polling requests: %v", n)
+	}
+}
+
+func TestDeviceTokenDenied(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeDeviceError(t, w, "access_denied")
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = c.DeviceToken(&ims.DeviceTokenRequest{
+		DeviceCode: "device-code",
+		ClientID:   "client-id",
+		Interval:   time.Millisecond,
+	})
+
+	imsErr, ok := ims.IsError(err)
+	if !ok {
+		t.Fatalf("expected IMS error")
+	}
+	if imsErr.ErrorCode != "access_denied" {
+		t.Fatalf("invalid error code: %v", imsErr.ErrorCode)
+	}
+}
+
+func TestDeviceTokenCancelled(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.DeviceTokenWithContext(ctx, &ims.DeviceTokenRequest{
+		DeviceCode: "device-code",
+		ClientID:   "client-id",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestDeviceTokenInvalidRequest(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.DeviceToken(&ims.DeviceTokenRequest{
+		ClientID: "client-id",
+	}); err == nil || err.Error() != "missing device code" {
+		t.Fatalf("invalid error: %v", err)
+	}
+
+	if _, err := c.DeviceToken(&ims.DeviceTokenRequest{
+		DeviceCode: "device-code",
+	}); err == nil || err.Error() != "missing client ID" {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
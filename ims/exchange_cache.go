@@ -0,0 +1,309 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultExchangeCacheMaxEntries   = 1000
+	defaultExchangeCacheExpiryMargin = time.Minute
+	defaultExchangeCacheCallTimeout  = 30 * time.Second
+)
+
+// ExchangeCacheConfig is the configuration for an ExchangeCache.
+type ExchangeCacheConfig struct {
+	// Client performs the exchanges. This field is required.
+	Client *Client
+	// MaxEntries is the maximum number of cached tokens. When the cache is
+	// full, the least recently used token is evicted. If not provided, it
+	// defaults to 1000.
+	MaxEntries int
+	// ExpiryMargin is how long before the expiration of a token the token is
+	// evicted from the cache. Tokens whose lifetime is shorter than the margin
+	// are not cached. If not provided, it defaults to one minute.
+	ExpiryMargin time.Duration
+	// CallTimeout is how long an exchange shared by concurrent callers can
+	// take. If not provided, it defaults to 30 seconds.
+	CallTimeout time.Duration
+}
+
+// ExchangeCache caches the results of On-Behalf-Of and cluster exchanges. The
+// tokens are cached by a hash of the parameters of the exchange, so the
+// subject tokens are never held as map keys. Concurrent identical exchanges
+// are collapsed into a single call to IMS. The call keeps the values of the
+// context of the first caller, but not its cancellation, so that a caller
+// giving up doesn't fail the others. Errors are not cached. The responses
+// returned are copies, and can be modified by the callers. An ExchangeCache is
+// safe for concurrent use.
+type ExchangeCache struct {
+	client       *Client
+	maxEntries   int
+	expiryMargin time.Duration
+	callTimeout  time.Duration
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	lru     *list.List
+	calls   map[[sha256.Size]byte]*exchangeCall
+}
+
+// exchangeCacheEntry is a cached token. The entry is valid until
+// ExpiryMargin before expires, the expiration of the token.
+type exchangeCacheEntry struct {
+	key     [sha256.Size]byte
+	value   interface{}
+	expires time.Time
+}
+
+// exchangeCall is an exchange in progress. The waiters block on done and then
+// read value, expires and err.
+type exchangeCall struct {
+	done    chan struct{}
+	value   interface{}
+	expires time.Time
+	err     error
+}
+
+// NewExchangeCache creates a new ExchangeCache.
+func NewExchangeCache(cfg *ExchangeCacheConfig) (*ExchangeCache, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("missing client")
+	}
+
+	if cfg.MaxEntries < 0 {
+		return nil, fmt.Errorf("invalid max entries: %v", cfg.MaxEntries)
+	}
+
+	if cfg.ExpiryMargin < 0 {
+		return nil, fmt.Errorf("invalid expiry margin: %v", cfg.ExpiryMargin)
+	}
+
+	if cfg.CallTimeout < 0 {
+		return nil, fmt.Errorf("invalid call timeout: %v", cfg.CallTimeout)
+	}
+
+	maxEntries := cfg.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = defaultExchangeCacheMaxEntries
+	}
+
+	expiryMargin := cfg.ExpiryMargin
+	if expiryMargin == 0 {
+		expiryMargin = defaultExchangeCacheExpiryMargin
+	}
+
+	callTimeout := cfg.CallTimeout
+	if callTimeout == 0 {
+		callTimeout = defaultExchangeCacheCallTimeout
+	}
+
+	return &ExchangeCache{
+		client:       cfg.Client,
+		maxEntries:   maxEntries,
+		expiryMargin: expiryMargin,
+		callTimeout:  callTimeout,
+		entries:      map[[sha256.Size]byte]*list.Element{},
+		lru:          list.New(),
+		calls:        map[[sha256.Size]byte]*exchangeCall{},
+	}, nil
+}
+
+// OBOExchangeWithContext returns the cached result of an On-Behalf-Of
+// exchange, or performs the exchange if no valid token is cached. The
+// ExpiresIn field of the response is the remaining lifetime of the token.
+func (c *ExchangeCache) OBOExchangeWithContext(ctx context.Context, r *OBOExchangeRequest) (*OBOExchangeResponse, error) {
+	key := exchangeCacheKey("obo", r.SubjectToken, r.ClientID, "", "", r.Scopes, r.Resource)
+
+	v, expires, err := c.do(ctx, key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		res, err := c.client.OBOExchangeWithContext(ctx, r)
+		if err != nil {
+			return nil, 0, err
+		}
+		return res, res.ExpiresIn, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := *v.(*OBOExchangeResponse)
+	res.Body = cloneBody(res.Body)
+	res.ExpiresIn = time.Until(expires)
+
+	return &res, nil
+}
+
+// OBOExchange is equivalent to OBOExchangeWithContext with a background
+// context.
+func (c *ExchangeCache) OBOExchange(r *OBOExchangeRequest) (*OBOExchangeResponse, error) {
+	return c.OBOExchangeWithContext(context.Background(), r)
+}
+
+// ClusterExchangeWithContext returns the cached result of a cluster exchange,
+// or performs the exchange if no valid token is cached. The ExpiresIn field of
+// the response is the remaining lifetime of the token.
+func (c *ExchangeCache) ClusterExchangeWithContext(ctx context.Context, r *ClusterExchangeRequest) (*ClusterExchangeResponse, error) {
+	key := exchangeCacheKey("cluster", r.UserToken, r.ClientID, r.UserID, r.OrgID, r.Scopes, r.Resource)
+
+	v, expires, err := c.do(ctx, key, func(ctx context.Context) (interface{}, time.Duration, error) {
+		res, err := c.client.ClusterExchangeWithContext(ctx, r)
+		if err != nil {
+			return nil, 0, err
+		}
+		return res, res.ExpiresIn, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := *v.(*ClusterExchangeResponse)
+	res.Body = cloneBody(res.Body)
+	res.ExpiresIn = time.Until(expires)
+
+	return &res, nil
+}
+
+// ClusterExchange is equivalent to ClusterExchangeWithContext with a
+// background context.
+func (c *ExchangeCache) ClusterExchange(r *ClusterExchangeRequest) (*ClusterExchangeResponse, error) {
+	return c.ClusterExchangeWithContext(context.Background(), r)
+}
+
+// Len returns the number of cached tokens, including the expired ones not
+// evicted yet.
+func (c *ExchangeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// do returns the cached value for the key if it didn't expire. Otherwise, it
+// calls fn, unless an identical call is already in progress, in which case it
+// waits for its result. fn runs in its own goroutine, with a context detached
+// from ctx, and every caller stops waiting when its own context is done.
+func (c *ExchangeCache) do(ctx context.Context, key [sha256.Size]byte, fn func(context.Context) (interface{}, time.Duration, error)) (interface{}, time.Time, error) {
+	c.mu.Lock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*exchangeCacheEntry)
+
+		if time.Now().Before(entry.expires.Add(-c.expiryMargin)) {
+			c.lru.MoveToFront(elem)
+			c.mu.Unlock()
+			return entry.value, entry.expires, nil
+		}
+
+		c.remove(elem)
+	}
+
+	call, ok := c.calls[key]
+	if !ok {
+		call = &exchangeCall{done: make(chan struct{})}
+		c.calls[key] = call
+		go c.call(context.WithoutCancel(ctx), key, call, fn)
+	}
+
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.expires, call.err
+	case <-ctx.Done():
+		return nil, time.Time{}, ctx.Err()
+	}
+}
+
+func (c *ExchangeCache) call(ctx context.Context, key [sha256.Size]byte, call *exchangeCall, fn func(context.Context) (interface{}, time.Duration, error)) {
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	var expiresIn time.Duration
+
+	call.value, expiresIn, call.err = fn(ctx)
+	call.expires = time.Now().Add(expiresIn)
+
+	c.mu.Lock()
+	delete(c.calls, key)
+	if call.err == nil && expiresIn > c.expiryMargin {
+		c.add(key, call.value, call.expires)
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+func (c *ExchangeCache) add(key [sha256.Size]byte, value interface{}, expires time.Time) {
+	c.entries[key] = c.lru.PushFront(&exchangeCacheEntry{
+		key:     key,
+		value:   value,
+		expires: expires,
+	})
+
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *ExchangeCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*exchangeCacheEntry).key)
+}
+
+func cloneBody(body []byte) []byte {
+	if body == nil {
+		return nil
+	}
+	return append([]byte(nil), body...)
+}
+
+// exchangeCacheKey hashes the parameters identifying an exchange. Every field
+// is prefixed by its length, so that different parameters can't produce the
+// same input to the hash function.
+func exchangeCacheKey(kind, token, clientID, userID, orgID string, scopes, resources []string) [sha256.Size]byte {
+	h := sha256.New()
+
+	writeString := func(s string) {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(s)))
+		h.Write(n[:])
+		h.Write([]byte(s))
+	}
+
+	writeStrings := func(ss []string) {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(ss)))
+		h.Write(n[:])
+		for _, s := range ss {
+			writeString(s)
+		}
+	}
+
+	writeString(kind)
+	writeString(token)
+	writeString(clientID)
+	writeString(userID)
+	writeString(orgID)
+	writeStrings(scopes)
+	writeStrings(resources)
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+
+	return key
+}
@@ -0,0 +1,350 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+// exchangeServer returns a server issuing tokens with the given lifetime in
+// seconds. The number of requests is counted in calls. If release is not nil,
+// every request blocks until release is closed.
+func exchangeServer(t *testing.T, expiresIn int, calls *int32, release chan struct{}) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+
+		if release != nil {
+			<-release
+		}
+
+		_, _ = fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": %d}`, n, expiresIn)
+	}))
+}
+
+func newExchangeCache(t *testing.T, url string, maxEntries int) *ims.ExchangeCache {
+	t.Helper()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: url})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	cache, err := ims.NewExchangeCache(&ims.ExchangeCacheConfig{
+		Client:     c,
+		MaxEntries: maxEntries,
+	})
+	if err != nil {
+		t.Fatalf("create cache: %v", err)
+	}
+
+	return cache
+}
+
+func oboRequest(subjectToken string, scopes ...string) *ims.OBOExchangeRequest {
+	return &ims.OBOExchangeRequest{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		SubjectToken: subjectToken,
+		Scopes:       scopes,
+	}
+}
+
+func TestExchangeCacheOBO(t *testing.T) {
+	var calls int32
+
+	s := exchangeServer(t, 3600, &calls, nil)
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 0)
+
+	first, err := cache.OBOExchange(oboRequest("user-token", "openid"))
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	second, err := cache.OBOExchange(oboRequest("user-token", "openid"))
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if first.AccessToken != second.AccessToken {
+		t.Fatalf("token not cached: %v, %v", first.AccessToken, second.AccessToken)
+	}
+	if second.ExpiresIn <= time.Hour-time.Minute || second.ExpiresIn > time.Hour {
+		t.Fatalf("invalid expiration: %v", second.ExpiresIn)
+	}
+
+	if _, err := cache.OBOExchange(oboRequest("user-token", "openid", "profile")); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if _, err := cache.OBOExchange(oboRequest("other-token", "openid")); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("invalid number of calls: %v", calls)
+	}
+}
+
+func TestExchangeCacheCluster(t *testing.T) {
+	var calls int32
+
+	s := exchangeServer(t, 3600, &calls, nil)
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 0)
+
+	request := func(orgID string) *ims.ClusterExchangeRequest {
+		return &ims.ClusterExchangeRequest{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			UserToken:    "user-token",
+			OrgID:        orgID,
+			Scopes:       []string{"openid"},
+		}
+	}
+
+	for _, orgID := range []string{"org-a", "org-a", "org-b", "org-b"} {
+		if _, err := cache.ClusterExchange(request(orgID)); err != nil {
+			t.Fatalf("exchange: %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("invalid number of calls: %v", calls)
+	}
+}
+
+func TestExchangeCacheShortLifetime(t *testing.T) {
+	var calls int32
+
+	s := exchangeServer(t, 30, &calls, nil)
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.OBOExchange(oboRequest("user-token", "openid")); err != nil {
+			t.Fatalf("exchange: %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("invalid number of calls: %v", calls)
+	}
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("invalid cache size: %v", n)
+	}
+}
+
+func TestExchangeCacheEviction(t *testing.T) {
+	var calls int32
+
+	s := exchangeServer(t, 3600, &calls, nil)
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 2)
+
+	for _, token := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := cache.OBOExchange(oboRequest(token, "openid")); err != nil {
+			t.Fatalf("exchange: %v", err)
+		}
+	}
+
+	// "b" is the least recently used token when "c" is added.
+	if calls != 4 {
+		t.Fatalf("invalid number of calls: %v", calls)
+	}
+	if n := cache.Len(); n != 2 {
+		t.Fatalf("invalid cache size: %v", n)
+	}
+}
+
+func TestExchangeCacheConcurrent(t *testing.T) {
+	var (
+		calls   int32
+		release = make(chan struct{})
+	)
+
+	s := exchangeServer(t, 3600, &calls, release)
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 0)
+
+	var (
+		wg     sync.WaitGroup
+		tokens = make([]string, 10)
+		errs   = make([]error, 10)
+	)
+
+	for i := range tokens {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			res, err := cache.OBOExchange(oboRequest("user-token", "openid"))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			tokens[i] = res.AccessToken
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range tokens {
+		if errs[i] != nil {
+			t.Fatalf("exchange: %v", errs[i])
+		}
+		if tokens[i] != "token-1" {
+			t.Fatalf("invalid token: %v", tokens[i])
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("invalid number of calls: %v", calls)
+	}
+}
+
+func TestExchangeCacheFirstCallerCancelled(t *testing.T) {
+	var (
+		calls   int32
+		release = make(chan struct{})
+	)
+
+	s := exchangeServer(t, 3600, &calls, release)
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := cache.OBOExchangeWithContext(ctx, oboRequest("user-token", "openid"))
+		firstErr <- err
+	}()
+
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	type result struct {
+		res *ims.OBOExchangeResponse
+		err error
+	}
+
+	second := make(chan result, 1)
+
+	go func() {
+		res, err := cache.OBOExchange(oboRequest("user-token", "openid"))
+		second <- result{res, err}
+	}()
+
+	// The first caller gives up while the second one waits for the same call.
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("invalid error: %v", err)
+	}
+
+	close(release)
+
+	r := <-second
+	if r.err != nil {
+		t.Fatalf("exchange: %v", r.err)
+	}
+	if r.res.AccessToken != "token-1" {
+		t.Fatalf("invalid token: %v", r.res.AccessToken)
+	}
+
+	if calls != 1 {
+		t.Fatalf("invalid number of calls: %v", calls)
+	}
+}
+
+func TestExchangeCacheResponseCopy(t *testing.T) {
+	var calls int32
+
+	s := exchangeServer(t, 3600, &calls, nil)
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 0)
+
+	first, err := cache.OBOExchange(oboRequest("user-token", "openid"))
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	want := string(first.Body)
+
+	for i := range first.Body {
+		first.Body[i] = 'x'
+	}
+
+	second, err := cache.OBOExchange(oboRequest("user-token", "openid"))
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if string(second.Body) != want {
+		t.Fatalf("invalid body: %s", second.Body)
+	}
+}
+
+func TestExchangeCacheError(t *testing.T) {
+	var calls int32
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "invalid_token"}`))
+	}))
+	defer s.Close()
+
+	cache := newExchangeCache(t, s.URL, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.OBOExchange(oboRequest("user-token", "openid")); err == nil {
+			t.Fatalf("expected error")
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("invalid number of calls: %v", calls)
+	}
+}
+
+func TestNewExchangeCacheInvalidConfig(t *testing.T) {
+	if _, err := ims.NewExchangeCache(&ims.ExchangeCacheConfig{}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
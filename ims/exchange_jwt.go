@@ -12,6 +12,7 @@ package ims
 
 import (
 	"context"
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -24,29 +25,54 @@ import (
 
 // MetaScope is a meta-scope that can be optionally added to a JWT token.
 //
-// Deprecated: use explicit claims in ExchangeJWTRequest.
+// Deprecated: use MetaScopes in ExchangeJWTRequest.
 type MetaScope int
 
 const (
 	// MetaScopeCloudManager is the meta-scope for Cloud Manager.
 	//
-	// Deprecated: use explicit claims in ExchangeJWTRequest.
+	// Deprecated: use MetaScopes in ExchangeJWTRequest.
 	MetaScopeCloudManager MetaScope = iota
 	// MetaScopeAdobeIO is the meta-scope for Adobe IO.
 	//
-	// Deprecated: use explicit claims in ExchangeJWTRequest.
+	// Deprecated: use MetaScopes in ExchangeJWTRequest.
 	MetaScopeAdobeIO
 	// MetaScopeAnalyticsBulkIngest is the meta-scope for Analytics Bulk Ingest.
 	//
-	// Deprecated: use explicit claims in ExchangeJWTRequest.
+	// Deprecated: use MetaScopes in ExchangeJWTRequest.
 	MetaScopeAnalyticsBulkIngest
 )
 
 // ExchangeJWTRequest contains the data for exchanging a JWT token with an
 // access token.
 type ExchangeJWTRequest struct {
-	// The private key for signing the JWT token. This field is required.
+	// The PEM-encoded RSA private key for signing the JWT token. Either this
+	// field or Signer is required.
 	PrivateKey []byte
+	// The signer for the JWT token. It allows signing the token without
+	// loading the private key in memory, e.g. when the key is held by an
+	// agent or a hardware module. A JWTSigner can be used to avoid parsing
+	// the private key on every exchange. Either this field or PrivateKey is
+	// required.
+	Signer crypto.Signer
+	// The signing algorithm, e.g. RS256, RS384 or PS256. If not provided,
+	// RS256 is used for RSA keys.
+	Algorithm string
+	// The key ID to add to the header of the JWT token. Optional.
+	KeyID string
+	// The ordered set of keys to try when signing the JWT token, for
+	// rotating keys without downtime. If provided, PrivateKey, Signer,
+	// Algorithm and KeyID must be empty. See JWTKey for details.
+	Keys []JWTKey
+	// The issue time of the JWT token. If not provided, the iat claim is
+	// omitted.
+	IssuedAt time.Time
+	// The time before which the JWT token must not be accepted. If not
+	// provided, the nbf claim is omitted.
+	NotBefore time.Time
+	// The unique identifier of the JWT token. If not provided, the jti claim
+	// is omitted.
+	JWTID string
 	// The expiration time for the access token. This field is required.
 	Expiration time.Time
 	// The issuer of the JWT token. It represents the identity of the
@@ -61,9 +87,19 @@ type ExchangeJWTRequest struct {
 	ClientSecret string
 	// The additional meta-scopes to add to the JWT token.
 	//
-	// Deprecated: use explicit claims in ExchangeJWTRequest.
+	// Deprecated: use MetaScopes in ExchangeJWTRequest.
 	MetaScope []MetaScope
-	// Additional claims to add to the JWT token.
+	// The names of the meta-scopes to add to the JWT token, e.g.
+	// MetaScopeAEMCloudAPI. Names are validated against the catalog of known
+	// meta-scopes, see IsKnownMetaScope.
+	MetaScopes []string
+	// Allow meta-scopes that are not in the catalog of known meta-scopes.
+	// This is an escape hatch for meta-scopes introduced after this version
+	// of the library.
+	AllowUnknownMetaScopes bool
+	// Additional claims to add to the JWT token. They can't override the
+	// claims generated for MetaScopes, but they can override the claims
+	// generated for the deprecated MetaScope.
 	Claims map[string]interface{}
 	// Resources provided to be added as access token audiences
 	Resources []string
@@ -77,13 +113,54 @@ type ExchangeJWTResponse struct {
 	AccessToken string
 	// ExpiresIn is the expiration for the token.
 	ExpiresIn time.Duration
+	// KeyID is the ID of the key that signed the accepted JWT token.
+	KeyID string
 }
 
 // ExchangeJWTWithContext exchanges a JWT token for an access token.
 func (c *Client) ExchangeJWTWithContext(ctx context.Context, r *ExchangeJWTRequest) (*ExchangeJWTResponse, error) {
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(r.PrivateKey)
+	claims, err := c.exchangeJWTClaims(r)
 	if err != nil {
-		return nil, fmt.Errorf("parse key: %v", err)
+		return nil, err
+	}
+
+	if len(r.Keys) > 0 {
+		if r.Signer != nil || len(r.PrivateKey) > 0 || r.Algorithm != "" || r.KeyID != "" {
+			return nil, fmt.Errorf("keys are mutually exclusive with private key, signer, algorithm and key ID")
+		}
+		return c.exchangeJWTWithKeys(ctx, r, claims)
+	}
+
+	var signed string
+
+	switch {
+	case r.Signer != nil && len(r.PrivateKey) > 0:
+		return nil, fmt.Errorf("private key and signer are mutually exclusive")
+	case r.Signer != nil:
+		signed, err = signJWT(claims, r.Signer, r.Algorithm, r.KeyID)
+	default:
+		signed, err = signJWTWithPEM(claims, r.PrivateKey, r.Algorithm, r.KeyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sign token: %v", err)
+	}
+
+	res, err := c.exchangeSignedJWT(ctx, r, signed)
+	if err != nil {
+		return nil, err
+	}
+
+	res.KeyID = r.KeyID
+
+	return res, nil
+}
+
+// signJWTWithPEM parses the PEM-encoded RSA private key and signs the claims
+// with it.
+func signJWTWithPEM(claims jwt.Claims, pemKey []byte, alg, keyID string) (string, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pemKey)
+	if err != nil {
+		return "", fmt.Errorf("parse key: %v", err)
 	}
 	// Zero the parsed private key after use to reduce the window where
 	// sensitive key material remains in memory.
@@ -94,6 +171,10 @@ func (c *Client) ExchangeJWTWithContext(ctx context.Context, r *ExchangeJWTReque
 		}
 	}()
 
+	return signJWT(claims, key, alg, keyID)
+}
+
+func (c *Client) exchangeJWTClaims(r *ExchangeJWTRequest) (jwt.MapClaims, error) {
 	claims := jwt.MapClaims{
 		"exp": r.Expiration.Unix(),
 		"iss": r.Issuer,
@@ -101,30 +182,38 @@ func (c *Client) ExchangeJWTWithContext(ctx context.Context, r *ExchangeJWTReque
 		"aud": fmt.Sprintf("%s/c/%s", c.url, r.ClientID),
 	}
 
-	for _, ms := range r.MetaScope {
-		switch ms {
-		case MetaScopeCloudManager:
-			claims[fmt.Sprintf("%v/s/ent_cloudmgr_sdk", c.url)] = true
-		case MetaScopeAdobeIO:
-			claims[fmt.Sprintf("%v/s/ent_adobeio_sdk", c.url)] = true
-		case MetaScopeAnalyticsBulkIngest:
-			claims[fmt.Sprintf("%v/s/ent_analytics_bulk_ingest_sdk", c.url)] = true
-		default:
-			return nil, fmt.Errorf("invalid meta-scope: %v", ms)
-		}
+	if !r.IssuedAt.IsZero() {
+		claims["iat"] = r.IssuedAt.Unix()
 	}
 
-	for k, v := range r.Claims {
-		claims[k] = v
+	if !r.NotBefore.IsZero() {
+		claims["nbf"] = r.NotBefore.Unix()
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if r.JWTID != "" {
+		claims["jti"] = r.JWTID
+	}
 
-	signed, err := token.SignedString(key)
+	metaScopes, err := c.metaScopeClaims(r)
 	if err != nil {
-		return nil, fmt.Errorf("sign token: %v", err)
+		return nil, err
 	}
 
+	for k := range metaScopes {
+		claims[k] = true
+	}
+
+	for k, v := range r.Claims {
+		if metaScopes[k] {
+			return nil, fmt.Errorf("claim conflicts with meta-scope: %v", k)
+		}
+		claims[k] = v
+	}
+
+	return claims, nil
+}
+
+func (c *Client) exchangeSignedJWT(ctx context.Context, r *ExchangeJWTRequest, signed string) (*ExchangeJWTResponse, error) {
 	data := url.Values{}
 
 	data.Set("client_id", r.ClientID)
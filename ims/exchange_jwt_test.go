@@ -11,17 +11,20 @@
 package ims_test
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/adobe/ims-go/ims"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func newPrivateKey(t *testing.T) []byte {
@@ -258,3 +261,149 @@ func TestExchangeJWTWithoutResourcesOmitsParam(t *testing.T) {
 		t.Fatalf("exchange JWT: %v", err)
 	}
 }
+
+// testSigner is a crypto.Signer that doesn't expose its private key, like a
+// key held by an agent or a hardware module.
+type testSigner struct {
+	key   crypto.Signer
+	calls int
+}
+
+func (s *testSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *testSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s.calls++
+	return s.key.Sign(rand, digest, opts)
+}
+
+func TestExchangeJWTWithSigner(t *testing.T) {
+	signer := &testSigner{key: newRSASigner(t)}
+
+	var (
+		issuedAt  = time.Now().Add(-time.Minute).Truncate(time.Second)
+		notBefore = time.Now().Add(-2 * time.Minute).Truncate(time.Second)
+	)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		token, err := jwt.Parse(r.PostForm.Get("jwt_token"), func(token *jwt.Token) (interface{}, error) {
+			return signer.Public(), nil
+		}, jwt.WithValidMethods([]string{"PS256"}))
+		if err != nil {
+			t.Fatalf("parse JWT: %v", err)
+		}
+		if kid := token.Header["kid"]; kid != "key-id" {
+			t.Fatalf("invalid key ID: %v", kid)
+		}
+
+		claims := token.Claims.(jwt.MapClaims)
+
+		if iat, _ := claims.GetIssuedAt(); iat == nil || !iat.Time.Equal(issuedAt) {
+			t.Fatalf("invalid issued at: %v", iat)
+		}
+		if nbf, _ := claims.GetNotBefore(); nbf == nil || !nbf.Time.Equal(notBefore) {
+			t.Fatalf("invalid not before: %v", nbf)
+		}
+		if jti := claims["jti"]; jti != "jwt-id" {
+			t.Fatalf("invalid JWT ID: %v", jti)
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600000}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	r, err := c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		Signer:       signer,
+		Algorithm:    "PS256",
+		KeyID:        "key-id",
+		IssuedAt:     issuedAt,
+		NotBefore:    notBefore,
+		JWTID:        "jwt-id",
+		Expiration:   time.Now().Add(24 * time.Hour),
+		Issuer:       "organization",
+		Subject:      "technical-user",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("exchange JWT: %v", err)
+	}
+	if r.AccessToken != "access-token" {
+		t.Fatalf("invalid access token: %v", r.AccessToken)
+	}
+	if signer.calls != 1 {
+		t.Fatalf("invalid number of signatures: %v", signer.calls)
+	}
+}
+
+func TestExchangeJWTPrivateKeyAlgorithm(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		token, _, err := jwt.NewParser().ParseUnverified(r.PostForm.Get("jwt_token"), jwt.MapClaims{})
+		if err != nil {
+			t.Fatalf("parse JWT: %v", err)
+		}
+		if alg := token.Method.Alg(); alg != "RS384" {
+			t.Fatalf("invalid algorithm: %v", alg)
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600000}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	// The default test key is too short for RS384.
+	privateKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(newRSASigner(t)),
+	})
+
+	if _, err := c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		PrivateKey:   privateKey,
+		Algorithm:    "RS384",
+		Expiration:   time.Now().Add(24 * time.Hour),
+		Issuer:       "organization",
+		Subject:      "technical-user",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	}); err != nil {
+		t.Fatalf("exchange JWT: %v", err)
+	}
+}
+
+func TestExchangeJWTPrivateKeyAndSigner(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		PrivateKey: newPrivateKey(t),
+		Signer:     newRSASigner(t),
+		Expiration: time.Now().Add(24 * time.Hour),
+		ClientID:   "client-id",
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if err.Error() != "private key and signer are mutually exclusive" {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+// JSONWebKey is a public key used by IMS to sign its tokens.
+type JSONWebKey struct {
+	// KeyID identifies the key in the header of the signed tokens.
+	KeyID string `json:"kid"`
+	// KeyType is the type of the key, e.g. "RSA".
+	KeyType string `json:"kty"`
+	// Algorithm is the signing algorithm used with the key, e.g. "RS256".
+	Algorithm string `json:"alg"`
+	// Use is the intended use of the key, e.g. "sig".
+	Use string `json:"use"`
+	// N is the base64url-encoded modulus of an RSA key.
+	N string `json:"n"`
+	// E is the base64url-encoded public exponent of an RSA key.
+	E string `json:"e"`
+}
+
+// PublicKey returns the public key described by the JSON Web Key. Only RSA
+// keys are supported.
+func (k *JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	if k.KeyType != "RSA" {
+		return nil, fmt.Errorf("unsupported key type: %v", k.KeyType)
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %v", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %v", err)
+	}
+
+	if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("invalid RSA key")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// GetKeysResponse is the response for GetKeys.
+type GetKeysResponse struct {
+	Response
+	// Keys are the public keys currently used by IMS.
+	Keys []JSONWebKey
+}
+
+// GetKeysWithContext reads the JSON Web Key Set holding the public keys used
+// by IMS to sign its tokens. It returns a non-nil response on success or an
+// error on failure.
+func (c *Client) GetKeysWithContext(ctx context.Context) (*GetKeysResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/ims/keys", c.url), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %v", err)
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("perform request: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	var body struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(res.Body, &body); err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return &GetKeysResponse{
+		Response: *res,
+		Keys:     body.Keys,
+	}, nil
+}
+
+// GetKeys is equivalent to GetKeysWithContext with a background context.
+func (c *Client) GetKeys() (*GetKeysResponse, error) {
+	return c.GetKeysWithContext(context.Background())
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestGetKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Fatalf("invalid method: %v", r.Method)
+		}
+		if r.URL.Path != "/ims/keys" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kid": "key-1",
+					"kty": "RSA",
+					"alg": "RS256",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		}); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{
+		URL: s.URL,
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	res, err := c.GetKeys()
+	if err != nil {
+		t.Fatalf("get keys: %v", err)
+	}
+
+	if len(res.Keys) != 1 || res.Keys[0].KeyID != "key-1" || res.Keys[0].Algorithm != "RS256" {
+		t.Fatalf("invalid keys: %+v", res.Keys)
+	}
+
+	pub, err := res.Keys[0].PublicKey()
+	if err != nil {
+		t.Fatalf("public key: %v", err)
+	}
+
+	if !key.PublicKey.Equal(pub) {
+		t.Fatalf("invalid public key")
+	}
+}
+
+func TestJSONWebKeyUnsupportedType(t *testing.T) {
+	k := ims.JSONWebKey{KeyType: "EC"}
+
+	if _, err := k.PublicKey(); err == nil {
+		t.Fatalf("expected error")
+	}
+}
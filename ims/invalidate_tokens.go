@@ -0,0 +1,160 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultInvalidateTokensConcurrency = 4
+
+// InvalidateTokensRequest is the request to InvalidateTokens.
+type InvalidateTokensRequest struct {
+	// Tokens is the list of tokens to invalidate.
+	Tokens []InvalidateTokenRequest
+	// Concurrency is the maximum number of invalidations performed at the
+	// same time. If not provided, it defaults to 4.
+	Concurrency int
+	// Interval is the minimum time between the start of two invalidations.
+	// If not provided, the invalidations are not rate limited.
+	Interval time.Duration
+}
+
+// InvalidateTokenResult is the result of the invalidation of a single token.
+type InvalidateTokenResult struct {
+	// Err is the error of the invalidation, or nil if the token was
+	// invalidated.
+	Err error
+	// IMSError contains the details of the error returned by IMS, if any.
+	IMSError *Error
+	// XDebugID is the X-Debug-Id header of the failed IMS response, if any.
+	XDebugID string
+}
+
+// InvalidateTokensResponse is the response of InvalidateTokens.
+type InvalidateTokensResponse struct {
+	// Results contains the result of every invalidation, in the same order
+	// as InvalidateTokensRequest.Tokens.
+	Results []InvalidateTokenResult
+}
+
+// InvalidateTokensWithContext invalidates multiple tokens concurrently. Unlike
+// a loop over InvalidateTokenWithContext, it doesn't stop at the first
+// failure. It returns a non-nil response with the result of every
+// invalidation, and an error joining the errors of the failed invalidations.
+// If the context is canceled, the tokens not invalidated yet fail with the
+// error of the context.
+func (c *Client) InvalidateTokensWithContext(ctx context.Context, r *InvalidateTokensRequest) (*InvalidateTokensResponse, error) {
+	if r.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency: %v", r.Concurrency)
+	}
+
+	if r.Interval < 0 {
+		return nil, fmt.Errorf("invalid interval: %v", r.Interval)
+	}
+
+	concurrency := r.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultInvalidateTokensConcurrency
+	}
+	if concurrency > len(r.Tokens) {
+		concurrency = len(r.Tokens)
+	}
+
+	var (
+		results = make([]InvalidateTokenResult, len(r.Tokens))
+		jobs    = make(chan int)
+		wg      sync.WaitGroup
+	)
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i] = invalidateTokenResult(c.InvalidateTokenWithContext(ctx, &r.Tokens[i]))
+			}
+		}()
+	}
+
+	var tick <-chan time.Time
+
+	if r.Interval > 0 {
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	dispatched := 0
+
+dispatch:
+	for dispatched < len(r.Tokens) {
+		if tick != nil && dispatched > 0 {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+
+		select {
+		case jobs <- dispatched:
+			dispatched++
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+
+	close(jobs)
+
+	for i := dispatched; i < len(r.Tokens); i++ {
+		results[i] = invalidateTokenResult(ctx.Err())
+	}
+
+	wg.Wait()
+
+	var errs []error
+
+	for i, res := range results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("invalidate token %d: %w", i, res.Err))
+		}
+	}
+
+	return &InvalidateTokensResponse{
+		Results: results,
+	}, errors.Join(errs...)
+}
+
+// InvalidateTokens is equivalent to InvalidateTokensWithContext with a
+// background context.
+func (c *Client) InvalidateTokens(r *InvalidateTokensRequest) (*InvalidateTokensResponse, error) {
+	return c.InvalidateTokensWithContext(context.Background(), r)
+}
+
+func invalidateTokenResult(err error) InvalidateTokenResult {
+	res := InvalidateTokenResult{
+		Err: err,
+	}
+
+	if imsErr, ok := IsError(err); ok {
+		res.IMSError = imsErr
+		res.XDebugID = imsErr.XDebugID
+	}
+
+	return res
+}
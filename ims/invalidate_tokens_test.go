@@ -0,0 +1,159 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestInvalidateTokens(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		if r.PostForm.Get("token") == "bad" {
+			w.Header().Set("X-Debug-Id", "debug-id")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_token", "error_description": "invalid token"}`))
+			return
+		}
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	var tokens []ims.InvalidateTokenRequest
+
+	for _, token := range []string{"a", "bad", "b", "c", "bad", "d"} {
+		tokens = append(tokens, ims.InvalidateTokenRequest{
+			Token:    token,
+			Type:     ims.AccessToken,
+			ClientID: "client-id",
+		})
+	}
+
+	res, err := c.InvalidateTokens(&ims.InvalidateTokensRequest{
+		Tokens:      tokens,
+		Concurrency: 2,
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if len(res.Results) != len(tokens) {
+		t.Fatalf("invalid number of results: %v", len(res.Results))
+	}
+
+	for i, result := range res.Results {
+		if tokens[i].Token != "bad" {
+			if result.Err != nil {
+				t.Fatalf("unexpected error for token %d: %v", i, result.Err)
+			}
+			continue
+		}
+
+		if result.IMSError == nil || result.IMSError.ErrorCode != "invalid_token" {
+			t.Fatalf("invalid IMS error for token %d: %v", i, result.Err)
+		}
+		if result.XDebugID != "debug-id" {
+			t.Fatalf("invalid X-Debug-Id for token %d: %v", i, result.XDebugID)
+		}
+	}
+
+	if imsErr, ok := ims.IsError(err); !ok || imsErr.ErrorCode != "invalid_token" {
+		t.Fatalf("invalid aggregated error: %v", err)
+	}
+
+	if maxInFlight > 2 {
+		t.Fatalf("concurrency exceeded: %v", maxInFlight)
+	}
+}
+
+func TestInvalidateTokensInterval(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tokens := make([]ims.InvalidateTokenRequest, 4)
+	for i := range tokens {
+		tokens[i] = ims.InvalidateTokenRequest{Token: "token", Type: ims.RefreshToken, ClientID: "client-id"}
+	}
+
+	start := time.Now()
+
+	if _, err := c.InvalidateTokens(&ims.InvalidateTokensRequest{
+		Tokens:      tokens,
+		Concurrency: 4,
+		Interval:    20 * time.Millisecond,
+	}); err != nil {
+		t.Fatalf("invalidate tokens: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("invalidations not rate limited: %v", elapsed)
+	}
+}
+
+func TestInvalidateTokensCanceled(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := c.InvalidateTokensWithContext(ctx, &ims.InvalidateTokensRequest{
+		Tokens: []ims.InvalidateTokenRequest{
+			{Token: "a", Type: ims.AccessToken, ClientID: "client-id"},
+			{Token: "b", Type: ims.AccessToken, ClientID: "client-id"},
+		},
+		Interval: time.Hour,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("invalid error: %v", err)
+	}
+	if !errors.Is(res.Results[1].Err, context.Canceled) {
+		t.Fatalf("invalid error for second token: %v", res.Results[1].Err)
+	}
+}
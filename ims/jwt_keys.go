@@ -0,0 +1,111 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTKey is one of the keys of a technical account, used when the exchange
+// must survive a certificate rotation. During a rotation, either the old or
+// the new certificate might be registered in IMS. The keys in
+// ExchangeJWTRequest.Keys are tried in order: if IMS rejects the signature of
+// a key, the exchange is retried with the next one.
+type JWTKey struct {
+	// KeyID identifies the key. It is added to the header of the JWT token
+	// and reported in ExchangeJWTResponse.KeyID. This field is required.
+	KeyID string
+	// Signer signs the JWT token. This field is required.
+	Signer crypto.Signer
+	// Algorithm is the signing algorithm. If not provided, RS256 is used for
+	// RSA keys.
+	Algorithm string
+	// NotBefore is the time before which the key must not be used. Optional.
+	NotBefore time.Time
+	// NotAfter is the time after which the key must not be used. Optional.
+	NotAfter time.Time
+}
+
+func (k *JWTKey) validAt(t time.Time) bool {
+	if !k.NotBefore.IsZero() && t.Before(k.NotBefore) {
+		return false
+	}
+
+	if !k.NotAfter.IsZero() && t.After(k.NotAfter) {
+		return false
+	}
+
+	return true
+}
+
+// isKeyRejected checks if the error means that IMS didn't accept the key
+// signing the JWT token, in which case the next key is tried.
+func isKeyRejected(err error) bool {
+	imsErr, ok := IsError(err)
+	if !ok {
+		return false
+	}
+
+	switch imsErr.ErrorCode {
+	case "invalid_token", "invalid_signature":
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) exchangeJWTWithKeys(ctx context.Context, r *ExchangeJWTRequest, claims jwt.MapClaims) (*ExchangeJWTResponse, error) {
+	var (
+		now  = time.Now()
+		errs []error
+	)
+
+	for i := range r.Keys {
+		key := &r.Keys[i]
+
+		if key.KeyID == "" {
+			return nil, fmt.Errorf("missing ID for key at index %d", i)
+		}
+
+		if !key.validAt(now) {
+			continue
+		}
+
+		signed, err := signJWT(claims, key.Signer, key.Algorithm, key.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("sign token with key %v: %v", key.KeyID, err)
+		}
+
+		res, err := c.exchangeSignedJWT(ctx, r, signed)
+		if err == nil {
+			res.KeyID = key.KeyID
+			return res, nil
+		}
+
+		if !isKeyRejected(err) {
+			return nil, err
+		}
+
+		errs = append(errs, fmt.Errorf("key %v rejected: %w", key.KeyID, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no key valid at %v", now.Format(time.RFC3339))
+	}
+
+	return nil, errors.Join(errs...)
+}
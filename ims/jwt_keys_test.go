@@ -0,0 +1,209 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"crypto"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyServer returns a server accepting only JWT tokens signed by the given
+// key. Every other token is rejected with the provided error code. The key IDs
+// of the received tokens are appended to kids.
+func keyServer(t *testing.T, accepted crypto.PublicKey, errorCode string, kids *[]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		token, err := jwt.Parse(r.PostForm.Get("jwt_token"), func(token *jwt.Token) (interface{}, error) {
+			*kids = append(*kids, token.Header["kid"].(string))
+			return accepted, nil
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			body := struct {
+				ErrorCode string `json:"error"`
+			}{
+				ErrorCode: errorCode,
+			}
+
+			if err := json.NewEncoder(w).Encode(&body); err != nil {
+				t.Fatalf("encode response: %v", err)
+			}
+
+			return
+		}
+
+		if !token.Valid {
+			t.Fatalf("invalid token")
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600000}`))
+	}))
+}
+
+func TestExchangeJWTKeyRotation(t *testing.T) {
+	var (
+		expired = newRSASigner(t)
+		old     = newRSASigner(t)
+		current = newRSASigner(t)
+		kids    []string
+	)
+
+	s := keyServer(t, current.Public(), "invalid_token", &kids)
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	r, err := c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		Keys: []ims.JWTKey{
+			{KeyID: "expired", Signer: expired, NotAfter: time.Now().Add(-time.Hour)},
+			{KeyID: "old", Signer: old},
+			{KeyID: "current", Signer: current, NotBefore: time.Now().Add(-time.Hour)},
+		},
+		Expiration:   time.Now().Add(time.Hour),
+		Issuer:       "organization",
+		Subject:      "technical-user",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("exchange JWT: %v", err)
+	}
+	if r.KeyID != "current" {
+		t.Fatalf("invalid key ID: %v", r.KeyID)
+	}
+	if len(kids) != 2 || kids[0] != "old" || kids[1] != "current" {
+		t.Fatalf("invalid keys tried: %v", kids)
+	}
+}
+
+func TestExchangeJWTKeyRotationAllRejected(t *testing.T) {
+	var kids []string
+
+	s := keyServer(t, newRSASigner(t).Public(), "invalid_signature", &kids)
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		Keys: []ims.JWTKey{
+			{KeyID: "a", Signer: newRSASigner(t)},
+			{KeyID: "b", Signer: newRSASigner(t)},
+		},
+		Expiration: time.Now().Add(time.Hour),
+		ClientID:   "client-id",
+	})
+
+	imsErr, ok := ims.IsError(err)
+	if !ok {
+		t.Fatalf("expected IMS error: %v", err)
+	}
+	if imsErr.ErrorCode != "invalid_signature" {
+		t.Fatalf("invalid error code: %v", imsErr.ErrorCode)
+	}
+	if len(kids) != 2 {
+		t.Fatalf("invalid keys tried: %v", kids)
+	}
+}
+
+func TestExchangeJWTKeyRotationOtherError(t *testing.T) {
+	var kids []string
+
+	s := keyServer(t, newRSASigner(t).Public(), "invalid_client", &kids)
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		Keys: []ims.JWTKey{
+			{KeyID: "a", Signer: newRSASigner(t)},
+			{KeyID: "b", Signer: newRSASigner(t)},
+		},
+		Expiration: time.Now().Add(time.Hour),
+		ClientID:   "client-id",
+	})
+
+	if imsErr, ok := ims.IsError(err); !ok || imsErr.ErrorCode != "invalid_client" {
+		t.Fatalf("invalid error: %v", err)
+	}
+	if len(kids) != 1 {
+		t.Fatalf("invalid keys tried: %v", kids)
+	}
+}
+
+func TestExchangeJWTKeyRotationInvalidKeys(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		request *ims.ExchangeJWTRequest
+		wantErr string
+	}{
+		{
+			name: "keys and signer",
+			request: &ims.ExchangeJWTRequest{
+				Signer: newRSASigner(t),
+				Keys:   []ims.JWTKey{{KeyID: "a", Signer: newRSASigner(t)}},
+			},
+			wantErr: "keys are mutually exclusive with private key, signer, algorithm and key ID",
+		},
+		{
+			name: "missing key ID",
+			request: &ims.ExchangeJWTRequest{
+				Keys: []ims.JWTKey{{Signer: newRSASigner(t)}},
+			},
+			wantErr: "missing ID for key at index 0",
+		},
+		{
+			name: "no valid key",
+			request: &ims.ExchangeJWTRequest{
+				Keys: []ims.JWTKey{{KeyID: "a", Signer: newRSASigner(t), NotBefore: time.Now().Add(time.Hour)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.Expiration = time.Now().Add(time.Hour)
+			tt.request.ClientID = "client-id"
+
+			if _, err := c.ExchangeJWT(tt.request); err == nil {
+				t.Fatalf("expected error")
+			} else if tt.wantErr != "" && err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// JWTSignerConfig is the configuration for a JWTSigner.
+type JWTSignerConfig struct {
+	// PrivateKey is the PEM-encoded private key. PKCS#1 RSA keys, SEC 1 EC
+	// keys, PKCS#8 keys and encrypted PKCS#8 keys are supported. This field
+	// is required.
+	PrivateKey []byte
+	// Passphrase returns the passphrase of an encrypted PKCS#8 key. It is
+	// invoked only if the key is encrypted. The returned slice is zeroed
+	// after use.
+	Passphrase func() ([]byte, error)
+}
+
+// JWTSigner is a crypto.Signer holding a parsed private key. It is meant to be
+// created once and reused for every JWT exchange, by setting it as the Signer
+// of an ExchangeJWTRequest, so that the key is not parsed again on every
+// call. A JWTSigner is safe for concurrent use. Close must be called when the
+// signer is no longer needed.
+type JWTSigner struct {
+	mu  sync.RWMutex
+	key crypto.Signer
+	pub crypto.PublicKey
+}
+
+// NewJWTSigner parses the private key in the configuration and returns a
+// JWTSigner for it.
+func NewJWTSigner(cfg *JWTSignerConfig) (*JWTSigner, error) {
+	block, _ := pem.Decode(cfg.PrivateKey)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var (
+		key crypto.Signer
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		if _, ok := block.Headers["DEK-Info"]; ok {
+			return nil, fmt.Errorf("legacy PEM encryption is not supported, use encrypted PKCS#8")
+		}
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		if _, ok := block.Headers["DEK-Info"]; ok {
+			return nil, fmt.Errorf("legacy PEM encryption is not supported, use encrypted PKCS#8")
+		}
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = parsePKCS8Signer(block.Bytes)
+	case "ENCRYPTED PRIVATE KEY":
+		key, err = parseEncryptedPKCS8Signer(block.Bytes, cfg.Passphrase)
+	default:
+		err = fmt.Errorf("unsupported PEM block type: %v", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse key: %v", err)
+	}
+
+	return &JWTSigner{
+		key: key,
+		pub: key.Public(),
+	}, nil
+}
+
+func parsePKCS8Signer(der []byte) (crypto.Signer, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %T", key)
+	}
+}
+
+func parseEncryptedPKCS8Signer(der []byte, passphrase func() ([]byte, error)) (crypto.Signer, error) {
+	if passphrase == nil {
+		return nil, fmt.Errorf("encrypted key requires a passphrase")
+	}
+
+	pass, err := passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("read passphrase: %v", err)
+	}
+	defer zero(pass)
+
+	plain, err := decryptPKCS8(der, pass)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(plain)
+
+	return parsePKCS8Signer(plain)
+}
+
+// Public implements crypto.Signer. It keeps returning the public key after
+// Close.
+func (s *JWTSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign implements crypto.Signer. It returns an error after Close.
+func (s *JWTSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.key == nil {
+		return nil, fmt.Errorf("signer closed")
+	}
+
+	return s.key.Sign(rand, digest, opts)
+}
+
+// Close zeroes the private key. It waits for the signatures in progress to
+// complete. Calling Close more than once has no effect.
+func (s *JWTSigner) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch k := s.key.(type) {
+	case *rsa.PrivateKey:
+		k.D.SetInt64(0)
+		for i := range k.Primes {
+			k.Primes[i].SetInt64(0)
+		}
+		if k.Precomputed.Dp != nil {
+			k.Precomputed.Dp.SetInt64(0)
+			k.Precomputed.Dq.SetInt64(0)
+			k.Precomputed.Qinv.SetInt64(0)
+		}
+	case *ecdsa.PrivateKey:
+		k.D.SetInt64(0)
+	}
+
+	s.key = nil
+
+	return nil
+}
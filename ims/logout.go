@@ -0,0 +1,157 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// LogoutURLConfig is the configuration for building a logout URL.
+type LogoutURLConfig struct {
+	// ClientID is the client ID. This field is required.
+	ClientID string
+	// IDTokenHint is the ID token of the user, identifying the session to
+	// terminate. Optional.
+	IDTokenHint string
+	// PostLogoutRedirectURI is the URL the user is redirected to after the
+	// logout. Optional.
+	PostLogoutRedirectURI string
+	// State is an opaque value passed back to PostLogoutRedirectURI. It
+	// requires PostLogoutRedirectURI. Optional.
+	State string
+}
+
+// LogoutURL builds a logout URL according to the provided configuration.
+func (c *Client) LogoutURL(cfg *LogoutURLConfig) (string, error) {
+	if cfg.ClientID == "" {
+		return "", fmt.Errorf("missing client ID")
+	}
+
+	if cfg.State != "" && cfg.PostLogoutRedirectURI == "" {
+		return "", fmt.Errorf("state requires a post logout redirect URI")
+	}
+
+	q := url.Values{}
+	q.Set("client_id", cfg.ClientID)
+
+	if cfg.IDTokenHint != "" {
+		q.Set("id_token_hint", cfg.IDTokenHint)
+	}
+
+	if cfg.PostLogoutRedirectURI != "" {
+		q.Set("post_logout_redirect_uri", cfg.PostLogoutRedirectURI)
+	}
+
+	if cfg.State != "" {
+		q.Set("state", cfg.State)
+	}
+
+	apiURL, err := url.Parse(fmt.Sprintf("%s/ims/logout/v1", c.url))
+	if err != nil {
+		return "", fmt.Errorf("parse URL: %v", err)
+	}
+
+	apiURL.RawQuery = q.Encode()
+
+	return apiURL.String(), nil
+}
+
+// LogoutRequest is the request for Logout.
+type LogoutRequest struct {
+	// LogoutURLConfig is the configuration of the logout URL returned in the
+	// response. Its ClientID is also used to invalidate the tokens.
+	LogoutURLConfig
+	// ClientSecret is the client secret used to invalidate the tokens.
+	// Optional.
+	ClientSecret string
+	// AccessToken is the access token of the session. Optional.
+	AccessToken string
+	// RefreshToken is the refresh token of the session. Optional.
+	RefreshToken string
+}
+
+// LogoutResponse is the response of Logout.
+type LogoutResponse struct {
+	// URL is the logout URL the user must be redirected to.
+	URL string
+}
+
+// LogoutWithContext terminates the session of a user. The refresh token and
+// the access token of the session are invalidated, together with the tokens
+// derived from them. It returns the logout URL the user must be redirected
+// to, in order to terminate the session in IMS as well.
+//
+// The invalidations are best-effort: both are attempted even if one of them
+// fails, for instance because the access token already expired. If any of
+// them fails, the response is returned together with the errors, so that the
+// user can still be redirected to the logout URL.
+func (c *Client) LogoutWithContext(ctx context.Context, r *LogoutRequest) (*LogoutResponse, error) {
+	logoutURL, err := c.LogoutURL(&r.LogoutURLConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+
+	tokens := []struct {
+		token     string
+		tokenType TokenType
+	}{
+		{r.RefreshToken, RefreshToken},
+		{r.AccessToken, AccessToken},
+	}
+
+	for _, t := range tokens {
+		if t.token == "" {
+			continue
+		}
+
+		if err := c.InvalidateTokenWithContext(ctx, &InvalidateTokenRequest{
+			Token:        t.token,
+			Type:         t.tokenType,
+			ClientID:     r.ClientID,
+			ClientSecret: r.ClientSecret,
+			Cascading:    true,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("invalidate %v: %w", t.tokenType, err))
+		}
+	}
+
+	return &LogoutResponse{
+		URL: logoutURL,
+	}, errors.Join(errs...)
+}
+
+// Logout is equivalent to LogoutWithContext with a background context.
+func (c *Client) Logout(r *LogoutRequest) (*LogoutResponse, error) {
+	return c.LogoutWithContext(context.Background(), r)
+}
+
+// ServeLogout terminates the session of a user like LogoutWithContext, using
+// the context of the HTTP request, and redirects the user to the logout URL.
+// The user is redirected even if the invalidation of the tokens fails, and the
+// error is returned for the caller to log. If the logout URL can't be built,
+// nothing is written to the response and the error is returned, so that the
+// caller can render an appropriate error page.
+func (c *Client) ServeLogout(w http.ResponseWriter, req *http.Request, r *LogoutRequest) error {
+	res, err := c.LogoutWithContext(req.Context(), r)
+	if res == nil {
+		return err
+	}
+
+	http.Redirect(w, req, res.URL, http.StatusFound)
+
+	return err
+}
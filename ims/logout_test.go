@@ -0,0 +1,213 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestLogoutURL(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	logoutURL, err := c.LogoutURL(&ims.LogoutURLConfig{
+		ClientID:              "client-id",
+		IDTokenHint:           "id-token",
+		PostLogoutRedirectURI: "https://example.com/signed-out",
+		State:                 "state",
+	})
+	if err != nil {
+		t.Fatalf("build URL: %v", err)
+	}
+
+	u, err := url.Parse(logoutURL)
+	if err != nil {
+		t.Fatalf("parse URL: %v", err)
+	}
+
+	if u.Host != "ims.endpoint" || u.Path != "/ims/logout/v1" {
+		t.Fatalf("invalid URL: %v", u)
+	}
+
+	q := u.Query()
+
+	want := map[string]string{
+		"client_id":                "client-id",
+		"id_token_hint":            "id-token",
+		"post_logout_redirect_uri": "https://example.com/signed-out",
+		"state":                    "state",
+	}
+	for k, v := range want {
+		if got := q.Get(k); got != v {
+			t.Fatalf("invalid %v: %v", k, got)
+		}
+	}
+}
+
+func TestLogoutURLInvalidConfig(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     *ims.LogoutURLConfig
+		wantErr string
+	}{
+		{
+			name:    "missing client ID",
+			cfg:     &ims.LogoutURLConfig{},
+			wantErr: "missing client ID",
+		},
+		{
+			name:    "state without redirect URI",
+			cfg:     &ims.LogoutURLConfig{ClientID: "client-id", State: "state"},
+			wantErr: "state requires a post logout redirect URI",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.LogoutURL(tt.cfg); err == nil {
+				t.Fatalf("expected error")
+			} else if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestServeLogout(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		invalidated []string
+	)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		if v := r.PostForm.Get("cascading"); v != "all" {
+			t.Fatalf("invalid cascading: %v", v)
+		}
+
+		mu.Lock()
+		invalidated = append(invalidated, r.PostForm.Get("token_type")+":"+r.PostForm.Get("token"))
+		mu.Unlock()
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+
+	if err := c.ServeLogout(w, httptest.NewRequest(http.MethodPost, "/logout", nil), &ims.LogoutRequest{
+		LogoutURLConfig: ims.LogoutURLConfig{
+			ClientID:              "client-id",
+			PostLogoutRedirectURI: "https://example.com/signed-out",
+		},
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+	}); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("invalid status code: %v", w.Code)
+	}
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("parse location: %v", err)
+	}
+	if location.Path != "/ims/logout/v1" {
+		t.Fatalf("invalid location: %v", location)
+	}
+
+	if len(invalidated) != 2 || invalidated[0] != "refresh_token:refresh-token" || invalidated[1] != "access_token:access-token" {
+		t.Fatalf("invalid invalidated tokens: %v", invalidated)
+	}
+}
+
+func TestServeLogoutError(t *testing.T) {
+	var calls int
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "invalid_token"}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+
+	err = c.ServeLogout(w, httptest.NewRequest(http.MethodPost, "/logout", nil), &ims.LogoutRequest{
+		LogoutURLConfig: ims.LogoutURLConfig{ClientID: "client-id"},
+		AccessToken:     "access-token",
+		RefreshToken:    "refresh-token",
+	})
+	if imsErr, ok := ims.IsError(err); !ok || imsErr.ErrorCode != "invalid_token" {
+		t.Fatalf("invalid error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("invalid number of invalidations: %v", calls)
+	}
+
+	// The user is signed out of IMS anyway.
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("invalid status code: %v", w.Code)
+	}
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("parse location: %v", err)
+	}
+	if location.Path != "/ims/logout/v1" {
+		t.Fatalf("invalid location: %v", location)
+	}
+}
+
+func TestServeLogoutInvalidConfig(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+
+	if err := c.ServeLogout(w, httptest.NewRequest(http.MethodPost, "/logout", nil), &ims.LogoutRequest{}); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if w.Header().Get("Location") != "" {
+		t.Fatalf("unexpected redirect")
+	}
+}
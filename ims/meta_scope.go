@@ -0,0 +1,123 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Names of the known meta-scopes, to be used in ExchangeJWTRequest.MetaScopes.
+const (
+	MetaScopeAdobeIOSDK                 = "ent_adobeio_sdk"
+	MetaScopeAEMCloudAPI                = "ent_aem_cloud_api"
+	MetaScopeAnalyticsBulkIngestSDK     = "ent_analytics_bulk_ingest_sdk"
+	MetaScopeAudienceManagerPlatformSDK = "ent_audiencemanagerplatform_sdk"
+	MetaScopeCampaignSDK                = "ent_campaign_sdk"
+	MetaScopeCloudManagerSDK            = "ent_cloudmgr_sdk"
+	MetaScopeDataServicesSDK            = "ent_dataservices_sdk"
+	MetaScopeMarketingSDK               = "ent_marketing_sdk"
+	MetaScopeReactorSDK                 = "ent_reactor_sdk"
+	MetaScopeSmartContentSDK            = "ent_smartcontent_sdk"
+	MetaScopeUserManagementSDK          = "ent_user_sdk"
+)
+
+var knownMetaScopes = map[string]bool{
+	MetaScopeAdobeIOSDK:                 true,
+	MetaScopeAEMCloudAPI:                true,
+	MetaScopeAnalyticsBulkIngestSDK:     true,
+	MetaScopeAudienceManagerPlatformSDK: true,
+	MetaScopeCampaignSDK:                true,
+	MetaScopeCloudManagerSDK:            true,
+	MetaScopeDataServicesSDK:            true,
+	MetaScopeMarketingSDK:               true,
+	MetaScopeReactorSDK:                 true,
+	MetaScopeSmartContentSDK:            true,
+	MetaScopeUserManagementSDK:          true,
+}
+
+// deprecatedMetaScopes maps the deprecated MetaScope values to their names.
+var deprecatedMetaScopes = map[MetaScope]string{
+	MetaScopeCloudManager:        MetaScopeCloudManagerSDK,
+	MetaScopeAdobeIO:             MetaScopeAdobeIOSDK,
+	MetaScopeAnalyticsBulkIngest: MetaScopeAnalyticsBulkIngestSDK,
+}
+
+// IsKnownMetaScope checks if the name belongs to the catalog of known
+// meta-scopes.
+func IsKnownMetaScope(name string) bool {
+	return knownMetaScopes[name]
+}
+
+// KnownMetaScopes returns the sorted names of the known meta-scopes.
+func KnownMetaScopes() []string {
+	names := make([]string, 0, len(knownMetaScopes))
+
+	for name := range knownMetaScopes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// metaScopeClaims validates the meta-scopes of the request and returns the
+// claim keys for them. A key maps to true if it can't be overridden by the
+// additional claims of the request. The keys of the deprecated MetaScope field
+// can be overridden, as they always could.
+func (c *Client) metaScopeClaims(r *ExchangeJWTRequest) (map[string]bool, error) {
+	claims := map[string]bool{}
+
+	for _, ms := range r.MetaScope {
+		name, ok := deprecatedMetaScopes[ms]
+		if !ok {
+			return nil, fmt.Errorf("invalid meta-scope: %v", ms)
+		}
+		claims[c.metaScopeClaim(name)] = false
+	}
+
+	for _, name := range r.MetaScopes {
+		if !validMetaScopeName(name) {
+			return nil, fmt.Errorf("invalid meta-scope name: %q", name)
+		}
+		if !r.AllowUnknownMetaScopes && !knownMetaScopes[name] {
+			return nil, fmt.Errorf("unknown meta-scope: %v", name)
+		}
+		claims[c.metaScopeClaim(name)] = true
+	}
+
+	return claims, nil
+}
+
+func (c *Client) metaScopeClaim(name string) string {
+	return fmt.Sprintf("%v/s/%v", c.url, name)
+}
+
+// validMetaScopeName checks that the name can be safely used as the last
+// segment of the claim key.
+func validMetaScopeName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9':
+		case r == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
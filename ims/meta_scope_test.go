@@ -0,0 +1,174 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestExchangeJWTMetaScopes(t *testing.T) {
+	var claims jwt.MapClaims
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		if _, _, err := jwt.NewParser().ParseUnverified(r.PostForm.Get("jwt_token"), &claims); err != nil {
+			t.Fatalf("parse JWT: %v", err)
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600000}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		PrivateKey:             newPrivateKey(t),
+		Expiration:             time.Now().Add(time.Hour),
+		ClientID:               "client-id",
+		MetaScope:              []ims.MetaScope{ims.MetaScopeCloudManager},
+		MetaScopes:             []string{ims.MetaScopeAEMCloudAPI, "ent_brand_new_sdk"},
+		AllowUnknownMetaScopes: true,
+		Claims:                 map[string]interface{}{"custom": "value"},
+	}); err != nil {
+		t.Fatalf("exchange JWT: %v", err)
+	}
+
+	for _, name := range []string{"ent_cloudmgr_sdk", "ent_aem_cloud_api", "ent_brand_new_sdk"} {
+		if v := claims[s.URL+"/s/"+name]; v != true {
+			t.Errorf("invalid claim for %v: %v", name, v)
+		}
+	}
+
+	if v := claims["custom"]; v != "value" {
+		t.Errorf("invalid custom claim: %v", v)
+	}
+}
+
+func TestExchangeJWTInvalidMetaScopes(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		request *ims.ExchangeJWTRequest
+		wantErr string
+	}{
+		{
+			name: "unknown meta-scope",
+			request: &ims.ExchangeJWTRequest{
+				MetaScopes: []string{"ent_aem_clod_api"},
+			},
+			wantErr: "unknown meta-scope: ent_aem_clod_api",
+		},
+		{
+			name: "malformed meta-scope",
+			request: &ims.ExchangeJWTRequest{
+				MetaScopes:             []string{"ent/../sdk"},
+				AllowUnknownMetaScopes: true,
+			},
+			wantErr: `invalid meta-scope name: "ent/../sdk"`,
+		},
+		{
+			name: "conflicting claim",
+			request: &ims.ExchangeJWTRequest{
+				MetaScopes: []string{ims.MetaScopeUserManagementSDK},
+				Claims: map[string]interface{}{
+					"http://ims.endpoint/s/ent_user_sdk": false,
+				},
+			},
+			wantErr: "claim conflicts with meta-scope: http://ims.endpoint/s/ent_user_sdk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.PrivateKey = newPrivateKey(t)
+			tt.request.Expiration = time.Now().Add(time.Hour)
+			tt.request.ClientID = "client-id"
+
+			if _, err := c.ExchangeJWT(tt.request); err == nil {
+				t.Fatalf("expected error")
+			} else if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestExchangeJWTLegacyMetaScopeOverride(t *testing.T) {
+	var claims jwt.MapClaims
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		if _, _, err := jwt.NewParser().ParseUnverified(r.PostForm.Get("jwt_token"), &claims); err != nil {
+			t.Fatalf("parse JWT: %v", err)
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600000}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	// Claims overlapping the deprecated meta-scopes are accepted, and win.
+
+	if _, err := c.ExchangeJWT(&ims.ExchangeJWTRequest{
+		PrivateKey: newPrivateKey(t),
+		Expiration: time.Now().Add(time.Hour),
+		ClientID:   "client-id",
+		MetaScope:  []ims.MetaScope{ims.MetaScopeCloudManager},
+		Claims:     map[string]interface{}{s.URL + "/s/ent_cloudmgr_sdk": "overridden"},
+	}); err != nil {
+		t.Fatalf("exchange JWT: %v", err)
+	}
+
+	if v := claims[s.URL+"/s/ent_cloudmgr_sdk"]; v != "overridden" {
+		t.Fatalf("invalid claim: %v", v)
+	}
+}
+
+func TestKnownMetaScopes(t *testing.T) {
+	names := ims.KnownMetaScopes()
+
+	for _, name := range names {
+		if !ims.IsKnownMetaScope(name) {
+			t.Fatalf("meta-scope not known: %v", name)
+		}
+	}
+
+	if !ims.IsKnownMetaScope("ent_user_sdk") {
+		t.Fatalf("ent_user_sdk should be known")
+	}
+
+	if ims.IsKnownMetaScope("ent_unknown_sdk") {
+		t.Fatalf("ent_unknown_sdk should not be known")
+	}
+}
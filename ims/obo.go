@@ -12,22 +12,21 @@ package ims
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
 	"time"
 )
 
-const defaultOBOGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
-
+// OBOExchangeRequest is the request for an On-Behalf-Of exchange, a token
+// exchange of a user access token for another access token.
 type OBOExchangeRequest struct {
 	ClientID     string
 	ClientSecret string
 	SubjectToken string
 	Scopes       []string
 	Resource     []string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ClientAuthenticator
 }
 
 type OBOExchangeResponse struct {
@@ -40,7 +39,7 @@ func (c *Client) validateOBOExchangeRequest(r *OBOExchangeRequest) error {
 	switch {
 	case r.ClientID == "":
 		return fmt.Errorf("missing client ID parameter")
-	case r.ClientSecret == "":
+	case r.ClientSecret == "" && r.ClientAuth == nil:
 		return fmt.Errorf("missing client secret parameter")
 	case r.SubjectToken == "":
 		return fmt.Errorf("missing subject token parameter (only access tokens are accepted)")
@@ -51,52 +50,31 @@ func (c *Client) validateOBOExchangeRequest(r *OBOExchangeRequest) error {
 	}
 }
 
+// OBOExchangeWithContext is a convenience wrapper around
+// TokenExchangeWithContext for On-Behalf-Of exchanges.
 func (c *Client) OBOExchangeWithContext(ctx context.Context, r *OBOExchangeRequest) (*OBOExchangeResponse, error) {
 	if err := c.validateOBOExchangeRequest(r); err != nil {
 		return nil, fmt.Errorf("invalid parameters for On-Behalf-Of exchange: %v", err)
 	}
 
-	data := url.Values{}
-	data.Set("grant_type", defaultOBOGrantType)
-	data.Set("client_id", r.ClientID)
-	data.Set("client_secret", r.ClientSecret)
-	data.Set("subject_token", r.SubjectToken)
-	data.Set("subject_token_type", "urn:ietf:params:oauth:token-type:access_token")
-	data.Set("requested_token_type", "urn:ietf:params:oauth:token-type:access_token")
-	data.Set("scope", strings.Join(r.Scopes, ","))
-
-	for _, res := range r.Resource {
-		data.Add("resource", res)
-	}
-
-	tokenURL := fmt.Sprintf("%s/ims/token/v4", c.url)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	res, err := c.do(req)
+	res, err := c.TokenExchangeWithContext(ctx, &TokenExchangeRequest{
+		ClientID:           r.ClientID,
+		ClientSecret:       r.ClientSecret,
+		ClientAuth:         r.ClientAuth,
+		SubjectToken:       r.SubjectToken,
+		SubjectTokenType:   TokenTypeURIAccessToken,
+		RequestedTokenType: TokenTypeURIAccessToken,
+		Resource:           r.Resource,
+		Scopes:             r.Scopes,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error performing request: %v", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return nil, errorResponse(res)
-	}
-
-	var body struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-	}
-	if err := json.Unmarshal(res.Body, &body); err != nil {
-		return nil, fmt.Errorf("decode response: %v", err)
+		return nil, err
 	}
 
 	return &OBOExchangeResponse{
-		Response:    *res,
-		AccessToken: body.AccessToken,
-		ExpiresIn:   time.Second * time.Duration(body.ExpiresIn),
+		Response:    res.Response,
+		AccessToken: res.AccessToken,
+		ExpiresIn:   res.ExpiresIn,
 	}, nil
 }
 
@@ -0,0 +1,152 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// OBOTarget describes the token required by a downstream service.
+type OBOTarget struct {
+	// Scopes is the list of scopes of the token. This field is required.
+	Scopes []string
+	// Resource is the list of resources of the token. Optional.
+	Resource []string
+}
+
+// OBOTransportConfig is the configuration for an OBOTransport.
+type OBOTransportConfig struct {
+	// Cache performs and caches the On-Behalf-Of exchanges. This field is
+	// required.
+	Cache *ExchangeCache
+	// ClientID is the client ID used for the exchanges. This field is
+	// required.
+	ClientID string
+	// ClientSecret is the client secret used for the exchanges.
+	ClientSecret string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ClientAuthenticator
+	// Targets maps the hosts of the downstream services to the tokens they
+	// require. A host is matched with or without port, the former taking
+	// precedence. This field is required.
+	Targets map[string]OBOTarget
+	// Base performs the requests once the token is injected. If not provided,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// OBOTransport is an http.RoundTripper that calls downstream services on
+// behalf of a user. The access token of the user is read from the context of
+// the request, see WithAccessToken, and exchanged for a token suitable for the
+// target host. The exchanged token is sent as a bearer token.
+//
+// Requests to hosts without a target are rejected, so that the token of a user
+// is never exchanged for, or sent to, an unexpected service.
+type OBOTransport struct {
+	cache        *ExchangeCache
+	clientID     string
+	clientSecret string
+	clientAuth   ClientAuthenticator
+	targets      map[string]OBOTarget
+	base         http.RoundTripper
+}
+
+// NewOBOTransport creates a new OBOTransport.
+func NewOBOTransport(cfg *OBOTransportConfig) (*OBOTransport, error) {
+	if cfg.Cache == nil {
+		return nil, fmt.Errorf("missing cache")
+	}
+
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("missing client ID")
+	}
+
+	if cfg.ClientSecret != "" && cfg.ClientAuth != nil {
+		return nil, fmt.Errorf("client secret and client authenticator are mutually exclusive")
+	}
+
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("missing targets")
+	}
+
+	targets := make(map[string]OBOTarget, len(cfg.Targets))
+
+	for host, target := range cfg.Targets {
+		if host == "" {
+			return nil, fmt.Errorf("empty target host")
+		}
+		if len(target.Scopes) == 0 {
+			return nil, fmt.Errorf("missing scopes for target host %v", host)
+		}
+		targets[host] = target
+	}
+
+	base := cfg.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &OBOTransport{
+		cache:        cfg.Cache,
+		clientID:     cfg.ClientID,
+		clientSecret: cfg.ClientSecret,
+		clientAuth:   cfg.ClientAuth,
+		targets:      targets,
+		base:         base,
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *OBOTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token(req)
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+
+	outReq := req.Clone(req.Context())
+	outReq.Header.Set("Authorization", "Bearer "+token)
+
+	return t.base.RoundTrip(outReq)
+}
+
+func (t *OBOTransport) token(req *http.Request) (string, error) {
+	target, ok := t.targets[req.URL.Host]
+	if !ok {
+		target, ok = t.targets[req.URL.Hostname()]
+	}
+	if !ok {
+		return "", fmt.Errorf("no On-Behalf-Of target for host %v", req.URL.Host)
+	}
+
+	subjectToken, ok := AccessTokenFromContext(req.Context())
+	if !ok {
+		return "", fmt.Errorf("missing access token in request context")
+	}
+
+	res, err := t.cache.OBOExchangeWithContext(req.Context(), &OBOExchangeRequest{
+		ClientID:     t.clientID,
+		ClientSecret: t.clientSecret,
+		ClientAuth:   t.clientAuth,
+		SubjectToken: subjectToken,
+		Scopes:       target.Scopes,
+		Resource:     target.Resource,
+	})
+	if err != nil {
+		return "", fmt.Errorf("On-Behalf-Of exchange: %w", err)
+	}
+
+	return res.AccessToken, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestOBOTransport(t *testing.T) {
+	var calls int32
+
+	imsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		_, _ = fmt.Fprintf(w, `{"access_token": "%v:%v", "expires_in": 3600}`,
+			r.PostForm.Get("subject_token"), r.PostForm.Get("scope"))
+	}))
+	defer imsServer.Close()
+
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer downstream.Close()
+
+	downstreamURL, err := url.Parse(downstream.URL)
+	if err != nil {
+		t.Fatalf("parse URL: %v", err)
+	}
+
+	transport, err := ims.NewOBOTransport(&ims.OBOTransportConfig{
+		Cache:        newExchangeCache(t, imsServer.URL, 0),
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		Targets: map[string]ims.OBOTarget{
+			downstreamURL.Host: {Scopes: []string{"read", "write"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create transport: %v", err)
+	}
+
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(ims.WithAccessToken(context.Background(), "user-token"), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			t.Fatalf("create request: %v", err)
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("perform request: %v", err)
+		}
+
+		var body [64]byte
+		n, _ := res.Body.Read(body[:])
+		_ = res.Body.Close()
+
+		if got := string(body[:n]); got != "Bearer user-token:read,write" {
+			t.Fatalf("invalid authorization: %v", got)
+		}
+
+		if v := req.Header.Get("Authorization"); v != "" {
+			t.Fatalf("original request modified: %v", v)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("invalid number of exchanges: %v", calls)
+	}
+}
+
+func TestOBOTransportErrors(t *testing.T) {
+	transport, err := ims.NewOBOTransport(&ims.OBOTransportConfig{
+		Cache:    newExchangeCache(t, "http://ims.endpoint", 0),
+		ClientID: "client-id",
+		Targets: map[string]ims.OBOTarget{
+			"service.example.com": {Scopes: []string{"read"}},
+		},
+		Base: http.NewFileTransport(http.Dir(".")),
+	})
+	if err != nil {
+		t.Fatalf("create transport: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		url     string
+		wantErr string
+	}{
+		{
+			name:    "unknown host",
+			ctx:     ims.WithAccessToken(context.Background(), "user-token"),
+			url:     "http://other.example.com/",
+			wantErr: "no On-Behalf-Of target for host other.example.com",
+		},
+		{
+			name:    "missing token",
+			ctx:     context.Background(),
+			url:     "http://service.example.com:8080/",
+			wantErr: "missing access token in request context",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("create request: %v", err)
+			}
+
+			if _, err := transport.RoundTrip(req); err == nil {
+				t.Fatalf("expected error")
+			} else if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewOBOTransportInvalidConfig(t *testing.T) {
+	cache := newExchangeCache(t, "http://ims.endpoint", 0)
+
+	tests := []struct {
+		name string
+		cfg  *ims.OBOTransportConfig
+	}{
+		{
+			name: "missing cache",
+			cfg: &ims.OBOTransportConfig{
+				ClientID: "client-id",
+				Targets:  map[string]ims.OBOTarget{"host": {Scopes: []string{"read"}}},
+			},
+		},
+		{
+			name: "missing targets",
+			cfg: &ims.OBOTransportConfig{
+				Cache:    cache,
+				ClientID: "client-id",
+			},
+		},
+		{
+			name: "missing scopes",
+			cfg: &ims.OBOTransportConfig{
+				Cache:    cache,
+				ClientID: "client-id",
+				Targets:  map[string]ims.OBOTarget{"host": {}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ims.NewOBOTransport(tt.cfg); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestAccessTokenFromContext(t *testing.T) {
+	if _, ok := ims.AccessTokenFromContext(context.Background()); ok {
+		t.Fatalf("unexpected token")
+	}
+
+	token, ok := ims.AccessTokenFromContext(ims.WithAccessToken(context.Background(), "token"))
+	if !ok || token != "token" {
+		t.Fatalf("invalid token: %v", token)
+	}
+}
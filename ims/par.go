@@ -0,0 +1,132 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PushedAuthorizationRequest is the request for pushing the parameters of an
+// authorization request to IMS (RFC 9126).
+type PushedAuthorizationRequest struct {
+	// AuthorizeURLConfig contains the parameters of the authorization
+	// request. The same validation rules of AuthorizeURL apply.
+	AuthorizeURLConfig
+	// ClientSecret is the client secret. This field is optional, since
+	// public clients can push authorization requests too.
+	ClientSecret string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ClientAuthenticator
+}
+
+// PushedAuthorizationResponse is the response of a pushed authorization
+// request.
+type PushedAuthorizationResponse struct {
+	Response
+	// RequestURI is the reference to the pushed authorization parameters. It
+	// must be passed to PushedAuthorizeURL to build the authorization URL.
+	RequestURI string
+	// ExpiresIn is the lifetime of the request URI.
+	ExpiresIn time.Duration
+}
+
+// PushAuthorizationWithContext pushes the parameters of an authorization
+// request to IMS and returns a request URI referencing them.
+func (c *Client) PushAuthorizationWithContext(ctx context.Context, r *PushedAuthorizationRequest) (*PushedAuthorizationResponse, error) {
+	data, err := authorizeParams(&r.AuthorizeURLConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	parURL := fmt.Sprintf("%s/ims/par/v1", c.url)
+
+	header, err := authenticateClient(r.ClientAuth, r.ClientID, r.ClientSecret, parURL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, parURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %v", err)
+	}
+
+	req.Header = header
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("perform request: %v", err)
+	}
+
+	// RFC 9126 mandates 201 Created, but be lenient with servers returning
+	// 200 OK.
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	var payload struct {
+		RequestURI string `json:"request_uri"`
+		ExpiresIn  int    `json:"expires_in"`
+	}
+
+	if err := json.Unmarshal(res.Body, &payload); err != nil {
+		return nil, fmt.Errorf("decode response: %v", err)
+	}
+
+	if payload.RequestURI == "" {
+		return nil, fmt.Errorf("missing request URI in response")
+	}
+
+	return &PushedAuthorizationResponse{
+		Response:   *res,
+		RequestURI: payload.RequestURI,
+		ExpiresIn:  time.Second * time.Duration(payload.ExpiresIn),
+	}, nil
+}
+
+// PushAuthorization is equivalent to PushAuthorizationWithContext with a
+// background context.
+func (c *Client) PushAuthorization(r *PushedAuthorizationRequest) (*PushedAuthorizationResponse, error) {
+	return c.PushAuthorizationWithContext(context.Background(), r)
+}
+
+// PushedAuthorizeURL builds an authorization URL referencing the parameters
+// previously pushed with PushAuthorization.
+func (c *Client) PushedAuthorizeURL(clientID, requestURI string) (string, error) {
+	if clientID == "" {
+		return "", fmt.Errorf("missing client ID")
+	}
+
+	if requestURI == "" {
+		return "", fmt.Errorf("missing request URI")
+	}
+
+	apiURL, err := url.Parse(fmt.Sprintf("%s/ims/authorize/v1", c.url))
+	if err != nil {
+		return "", fmt.Errorf("parse URL: %v", err)
+	}
+
+	q := url.Values{}
+
+	q.Set("client_id", clientID)
+	q.Set("request_uri", requestURI)
+
+	apiURL.RawQuery = q.Encode()
+
+	return apiURL.String(), nil
+}
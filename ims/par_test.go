@@ -0,0 +1,172 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestPushAuthorization(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("invalid method: %v", r.Method)
+		}
+		if r.URL.Path != "/ims/par/v1" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+		if len(r.URL.RawQuery) != 0 {
+			t.Fatalf("unexpected query: %v", r.URL.RawQuery)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if v := r.PostForm.Get("client_id"); v != "client-id" {
+			t.Fatalf("invalid client ID: %v", v)
+		}
+		if v := r.PostForm.Get("client_secret"); v != "client-secret" {
+			t.Fatalf("invalid client secret: %v", v)
+		}
+		if v := r.PostForm.Get("scope"); v != "openid,AdobeID" {
+			t.Fatalf("invalid scope: %v", v)
+		}
+		if v := r.PostForm.Get("response_type"); v != "code" {
+			t.Fatalf("invalid response type: %v", v)
+		}
+		if v := r.PostForm.Get("state"); v != "state" {
+			t.Fatalf("invalid state: %v", v)
+		}
+		if v := r.PostForm.Get("code_challenge_method"); v != "S256" {
+			t.Fatalf("invalid code challenge method: %v", v)
+		}
+		if v := r.PostForm["resource"]; len(v) != 2 {
+			t.Fatalf("invalid resources: %v", v)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"request_uri": "urn:ietf:params:oauth:request_uri:abc", "expires_in": 60}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	r, err := c.PushAuthorization(&ims.PushedAuthorizationRequest{
+		AuthorizeURLConfig: ims.AuthorizeURLConfig{
+			ClientID:     "client-id",
+			Scope:        []string{"openid", "AdobeID"},
+			State:        "state",
+			CodeVerifier: "verifier",
+			Resource:     []string{"https://a.example.com", "https://b.example.com"},
+		},
+		ClientSecret: "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("push authorization: %v", err)
+	}
+	if r.RequestURI != "urn:ietf:params:oauth:request_uri:abc" {
+		t.Fatalf("invalid request URI: %v", r.RequestURI)
+	}
+	if r.ExpiresIn != 60*time.Second {
+		t.Fatalf("invalid expiration: %v", r.ExpiresIn)
+	}
+
+	u, err := c.PushedAuthorizeURL("client-id", r.RequestURI)
+	if err != nil {
+		t.Fatalf("pushed authorize URL: %v", err)
+	}
+
+	authURL, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if authURL.Path != "/ims/authorize/v1" {
+		t.Fatalf("invalid path: %v", authURL.Path)
+	}
+
+	q := authURL.Query()
+
+	if len(q) != 2 {
+		t.Fatalf("unexpected parameters: %v", q)
+	}
+	if v := q.Get("client_id"); v != "client-id" {
+		t.Fatalf("invalid client ID: %v", v)
+	}
+	if v := q.Get("request_uri"); v != "urn:ietf:params:oauth:request_uri:abc" {
+		t.Fatalf("invalid request URI: %v", v)
+	}
+}
+
+func TestPushAuthorizationError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+
+		body := struct {
+			ErrorCode    string `json:"error"`
+			ErrorMessage string `json:"error_description"`
+		}{
+			ErrorCode:    "invalid_request",
+			ErrorMessage: "error-message",
+		}
+
+		if err := json.NewEncoder(w).Encode(&body); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = c.PushAuthorization(&ims.PushedAuthorizationRequest{
+		AuthorizeURLConfig: ims.AuthorizeURLConfig{
+			ClientID: "client-id",
+			Scope:    []string{"openid"},
+		},
+	})
+
+	imsErr, ok := ims.IsError(err)
+	if !ok {
+		t.Fatalf("expected IMS error")
+	}
+	if imsErr.ErrorCode != "invalid_request" {
+		t.Fatalf("invalid error code: %v", imsErr.ErrorCode)
+	}
+}
+
+func TestPushAuthorizationInvalidRequest(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.PushAuthorization(&ims.PushedAuthorizationRequest{
+		AuthorizeURLConfig: ims.AuthorizeURLConfig{
+			ClientID: "client-id",
+		},
+	}); err == nil || err.Error() != "missing scope" {
+		t.Fatalf("invalid error: %v", err)
+	}
+
+	if _, err := c.PushedAuthorizeURL("client-id", ""); err == nil || err.Error() != "missing request URI" {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
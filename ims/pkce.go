@@ -0,0 +1,75 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// CodeChallengeMethodS256 is the only code challenge method used by this
+// package.
+const CodeChallengeMethodS256 = "S256"
+
+// PKCE is a code verifier and its code challenge, as described in RFC 7636.
+// The code verifier is kept by the client and sent with the token request,
+// while the code challenge is sent with the authorization request.
+type PKCE struct {
+	CodeVerifier        string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// NewPKCE generates a random code verifier and computes its S256 code
+// challenge.
+func NewPKCE() (*PKCE, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate code verifier: %v", err)
+	}
+
+	return &PKCE{
+		CodeVerifier:        verifier,
+		CodeChallenge:       CodeChallenge(verifier),
+		CodeChallengeMethod: CodeChallengeMethodS256,
+	}, nil
+}
+
+// CodeChallenge computes the S256 code challenge of a code verifier.
+func CodeChallenge(codeVerifier string) string {
+	h := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// NewState generates a random, URL-safe value for the state parameter of an
+// authorization request.
+func NewState() (string, error) {
+	state, err := randomString(32)
+	if err != nil {
+		return "", fmt.Errorf("generate state: %v", err)
+	}
+
+	return state, nil
+}
+
+// randomString returns n random bytes encoded with the unpadded, URL-safe
+// base64 alphabet.
+func randomString(n int) (string, error) {
+	data := make([]byte, n)
+
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
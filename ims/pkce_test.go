@@ -0,0 +1,61 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestCodeChallenge(t *testing.T) {
+	// Example from RFC 7636, appendix B.
+	if v := ims.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); v != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Fatalf("invalid code challenge: %v", v)
+	}
+}
+
+func TestNewPKCE(t *testing.T) {
+	p, err := ims.NewPKCE()
+	if err != nil {
+		t.Fatalf("generate PKCE: %v", err)
+	}
+
+	if n := len(p.CodeVerifier); n < 43 || n > 128 {
+		t.Fatalf("invalid code verifier length: %v", n)
+	}
+	if p.CodeChallenge != ims.CodeChallenge(p.CodeVerifier) {
+		t.Fatalf("invalid code challenge: %v", p.CodeChallenge)
+	}
+	if p.CodeChallengeMethod != "S256" {
+		t.Fatalf("invalid code challenge method: %v", p.CodeChallengeMethod)
+	}
+
+	other, err := ims.NewPKCE()
+	if err != nil {
+		t.Fatalf("generate PKCE: %v", err)
+	}
+	if other.CodeVerifier == p.CodeVerifier {
+		t.Fatalf("code verifier not random")
+	}
+}
+
+func TestNewState(t *testing.T) {
+	state, err := ims.NewState()
+	if err != nil {
+		t.Fatalf("generate state: %v", err)
+	}
+
+	if url.QueryEscape(state) != state {
+		t.Fatalf("state not URL-safe: %v", state)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// The standard library doesn't support encrypted PKCS#8 keys. This file
+// implements the PBES2 scheme (RFC 8018) with PBKDF2 and AES-CBC, which is
+// what OpenSSL produces by default.
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 decrypts an encrypted PKCS#8 structure and returns the DER
+// encoding of the unencrypted PKCS#8 private key. The caller should zero the
+// returned slice after use.
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("decode encrypted key: %v", err)
+	}
+
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported key encryption algorithm: %v", info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("decode PBES2 parameters: %v", err)
+	}
+
+	var keyLen int
+
+	switch {
+	case params.EncryptionScheme.Algorithm.Equal(oidAES128CBC):
+		keyLen = 16
+	case params.EncryptionScheme.Algorithm.Equal(oidAES192CBC):
+		keyLen = 24
+	case params.EncryptionScheme.Algorithm.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, fmt.Errorf("unsupported key encryption scheme: %v", params.EncryptionScheme.Algorithm)
+	}
+
+	var iv []byte
+
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("decode encryption scheme parameters: %v", err)
+	}
+
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid IV length: %v", len(iv))
+	}
+
+	key, err := deriveKey(&params.KeyDerivationFunc, passphrase, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %v", err)
+	}
+
+	data := info.EncryptedData
+
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid encrypted data length: %v", len(data))
+	}
+
+	plain := make([]byte, len(data))
+
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	// A wrong passphrase almost always results in an invalid padding.
+
+	padding := int(plain[len(plain)-1])
+
+	if padding == 0 || padding > aes.BlockSize {
+		zero(plain)
+		return nil, fmt.Errorf("decrypt key: invalid passphrase")
+	}
+
+	for _, b := range plain[len(plain)-padding:] {
+		if int(b) != padding {
+			zero(plain)
+			return nil, fmt.Errorf("decrypt key: invalid passphrase")
+		}
+	}
+
+	return plain[:len(plain)-padding], nil
+}
+
+func deriveKey(kdf *pkix.AlgorithmIdentifier, passphrase []byte, keyLen int) ([]byte, error) {
+	if !kdf.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function: %v", kdf.Algorithm)
+	}
+
+	var params pbkdf2Params
+
+	if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("decode PBKDF2 parameters: %v", err)
+	}
+
+	if params.KeyLength != 0 && params.KeyLength != keyLen {
+		return nil, fmt.Errorf("invalid PBKDF2 key length: %v", params.KeyLength)
+	}
+
+	if params.IterationCount <= 0 {
+		return nil, fmt.Errorf("invalid PBKDF2 iteration count: %v", params.IterationCount)
+	}
+
+	var h func() hash.Hash
+
+	switch prf := params.PRF.Algorithm; {
+	case len(prf) == 0, prf.Equal(oidHMACWithSHA1):
+		h = sha1.New
+	case prf.Equal(oidHMACWithSHA256):
+		h = sha256.New
+	case prf.Equal(oidHMACWithSHA384):
+		h = sha512.New384
+	case prf.Equal(oidHMACWithSHA512):
+		h = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudo-random function: %v", prf)
+	}
+
+	return pbkdf2(passphrase, params.Salt, params.IterationCount, keyLen, h), nil
+}
+
+// pbkdf2 derives a key from the password as described in RFC 8018, section
+// 5.2.
+func pbkdf2(password, salt []byte, iter, keyLen int, h func() hash.Hash) []byte {
+	prf := hmac.New(h, password)
+	hashLen := prf.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var (
+		buf = make([]byte, 4)
+		dk  = make([]byte, 0, numBlocks*hashLen)
+		u   = make([]byte, hashLen)
+	)
+
+	for block := 1; block <= numBlocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf, uint32(block))
+		prf.Write(buf)
+
+		dk = prf.Sum(dk)
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+
+		for n := 2; n <= iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = u[:0]
+			u = prf.Sum(u)
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+
+	zero(u)
+	zero(dk[keyLen:])
+
+	return dk[:keyLen]
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
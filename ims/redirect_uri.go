@@ -0,0 +1,116 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// RedirectURIPolicy controls which redirect URIs are accepted by
+// ValidateRedirectURI.
+type RedirectURIPolicy struct {
+	// RequireLoopbackIP rejects "localhost" as a loopback host, and only
+	// accepts loopback IP literals like 127.0.0.1 and [::1]. IP literals are
+	// preferred, because "localhost" might resolve to a non-loopback
+	// interface (RFC 8252, section 8.3).
+	RequireLoopbackIP bool
+	// CustomSchemes is the list of private-use URI schemes accepted for
+	// native apps, e.g. "com.example.app". Every scheme must be a reverse
+	// domain name (RFC 8252, section 7.1).
+	CustomSchemes []string
+}
+
+// ValidateRedirectURI checks that the redirect URI follows the OAuth security
+// best practices. The URI must be absolute and without fragment. It must use
+// HTTPS, unless its host is a loopback address or its scheme is one of the
+// custom schemes allowed by the policy. If the policy is nil, the zero policy
+// is used.
+func ValidateRedirectURI(uri string, p *RedirectURIPolicy) error {
+	if err := validateRedirectURI(uri, p); err != nil {
+		return fmt.Errorf("invalid redirect URI %q: %v", uri, err)
+	}
+
+	return nil
+}
+
+func validateRedirectURI(uri string, p *RedirectURIPolicy) error {
+	if p == nil {
+		p = &RedirectURIPolicy{}
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("parse: %v", err)
+	}
+
+	if !u.IsAbs() {
+		return fmt.Errorf("not an absolute URI")
+	}
+
+	if u.Fragment != "" || strings.Contains(uri, "#") {
+		return fmt.Errorf("fragment not allowed")
+	}
+
+	if u.User != nil {
+		return fmt.Errorf("user info not allowed")
+	}
+
+	switch u.Scheme {
+	case "https", "http":
+		return validateRedirectURIHost(u, p)
+	default:
+		return validateRedirectURIScheme(u, p)
+	}
+}
+
+func validateRedirectURIHost(u *url.URL, p *RedirectURIPolicy) error {
+	host := u.Hostname()
+
+	if host == "" {
+		return fmt.Errorf("missing host")
+	}
+
+	if strings.EqualFold(host, "localhost") {
+		if p.RequireLoopbackIP {
+			return fmt.Errorf("localhost not allowed, use a loopback IP literal like 127.0.0.1 or [::1]")
+		}
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+
+	if u.Scheme != "https" {
+		return fmt.Errorf("HTTPS required for non-loopback host %v", host)
+	}
+
+	return nil
+}
+
+func validateRedirectURIScheme(u *url.URL, p *RedirectURIPolicy) error {
+	for _, scheme := range p.CustomSchemes {
+		if !strings.EqualFold(u.Scheme, scheme) {
+			continue
+		}
+
+		if !strings.Contains(scheme, ".") {
+			return fmt.Errorf("custom scheme %v is not a reverse domain name", scheme)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("scheme %v not allowed", u.Scheme)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestValidateRedirectURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		policy  *ims.RedirectURIPolicy
+		wantErr string
+	}{
+		{uri: "https://example.com/callback"},
+		{uri: "http://127.0.0.1:8000/callback"},
+		{uri: "http://[::1]:8000/callback"},
+		{uri: "http://localhost:8000/callback"},
+		{uri: "com.example.app:/callback", policy: &ims.RedirectURIPolicy{CustomSchemes: []string{"com.example.app"}}},
+		{
+			uri:     "/callback",
+			wantErr: `invalid redirect URI "/callback": not an absolute URI`,
+		},
+		{
+			uri:     "https://example.com/callback#fragment",
+			wantErr: `invalid redirect URI "https://example.com/callback#fragment": fragment not allowed`,
+		},
+		{
+			uri:     "https://user@example.com/callback",
+			wantErr: `invalid redirect URI "https://user@example.com/callback": user info not allowed`,
+		},
+		{
+			uri:     "http://example.com/callback",
+			wantErr: `invalid redirect URI "http://example.com/callback": HTTPS required for non-loopback host example.com`,
+		},
+		{
+			uri:     "http://localhost:8000/callback",
+			policy:  &ims.RedirectURIPolicy{RequireLoopbackIP: true},
+			wantErr: `invalid redirect URI "http://localhost:8000/callback": localhost not allowed, use a loopback IP literal like 127.0.0.1 or [::1]`,
+		},
+		{
+			uri:     "com.example.app:/callback",
+			wantErr: `invalid redirect URI "com.example.app:/callback": scheme com.example.app not allowed`,
+		},
+		{
+			uri:     "myapp:/callback",
+			policy:  &ims.RedirectURIPolicy{CustomSchemes: []string{"myapp"}},
+			wantErr: `invalid redirect URI "myapp:/callback": custom scheme myapp is not a reverse domain name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			err := ims.ValidateRedirectURI(tt.uri, tt.policy)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
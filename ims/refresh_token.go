@@ -32,6 +32,9 @@ type RefreshTokenRequest struct {
 	// Scope is the scope list in the refresh token. This field is optional. If
 	// provided, it must be a subset of the scopes in the request token.
 	Scope []string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ClientAuthenticator
 }
 
 // RefreshTokenResponse is the response of an access token refresh.
@@ -55,7 +58,7 @@ func (c *Client) RefreshTokenWithContext(ctx context.Context, r *RefreshTokenReq
 		return nil, fmt.Errorf("missing client ID")
 	}
 
-	if r.ClientSecret == "" {
+	if r.ClientSecret == "" && r.ClientAuth == nil {
 		return nil, fmt.Errorf("missing client secret")
 	}
 
@@ -64,17 +67,24 @@ func (c *Client) RefreshTokenWithContext(ctx context.Context, r *RefreshTokenReq
 	data.Set("grant_type", "refresh_token")
 	data.Set("refresh_token", r.RefreshToken)
 	data.Set("client_id", r.ClientID)
-	data.Set("client_secret", r.ClientSecret)
 
 	if len(r.Scope) > 0 {
 		data.Set("scope", strings.Join(r.Scope, ","))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/ims/token/v2", c.url), strings.NewReader(data.Encode()))
+	tokenURL := fmt.Sprintf("%s/ims/token/v2", c.url)
+
+	header, err := authenticateClient(r.ClientAuth, r.ClientID, r.ClientSecret, tokenURL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %v", err)
 	}
 
+	req.Header = header
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	res, err := c.do(req)
@@ -0,0 +1,175 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	minStateKeySize      = 32
+	defaultStateLifetime = 10 * time.Minute
+)
+
+// StateSignerConfig is the configuration for a StateSigner.
+type StateSignerConfig struct {
+	// Keys are the HMAC keys, at least 32 bytes long each. The first key
+	// signs new states, while every key is used to verify states, so that
+	// keys can be rotated without failing the logins in progress. At least
+	// one key is required.
+	Keys [][]byte
+	// Lifetime is how long a signed state is valid. If not provided, it
+	// defaults to ten minutes.
+	Lifetime time.Duration
+}
+
+// StateSigner creates and verifies self-contained state parameters. The state
+// is signed with HMAC-SHA256, so that the callback of an authorization request
+// can be verified by any instance sharing the keys, without server-side
+// storage. The content of the state is signed, not encrypted: it must not
+// contain secrets.
+type StateSigner struct {
+	keys     [][]byte
+	lifetime time.Duration
+}
+
+// State is the content of a signed state.
+type State struct {
+	// ReturnURL is where the user should land after the login. Optional.
+	ReturnURL string
+	// Nonce is a value bound to the ID token. Optional.
+	Nonce string
+	// ExpiresAt is when the state expires. It is set by Sign if not
+	// provided.
+	ExpiresAt time.Time
+}
+
+type statePayload struct {
+	ID        string `json:"id"`
+	ReturnURL string `json:"ret,omitempty"`
+	Nonce     string `json:"non,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// NewStateSigner creates a new StateSigner.
+func NewStateSigner(cfg *StateSignerConfig) (*StateSigner, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("missing keys")
+	}
+
+	keys := make([][]byte, len(cfg.Keys))
+
+	for i, key := range cfg.Keys {
+		if len(key) < minStateKeySize {
+			return nil, fmt.Errorf("key at index %d shorter than %d bytes", i, minStateKeySize)
+		}
+		keys[i] = append([]byte(nil), key...)
+	}
+
+	if cfg.Lifetime < 0 {
+		return nil, fmt.Errorf("invalid lifetime: %v", cfg.Lifetime)
+	}
+
+	lifetime := cfg.Lifetime
+	if lifetime == 0 {
+		lifetime = defaultStateLifetime
+	}
+
+	return &StateSigner{
+		keys:     keys,
+		lifetime: lifetime,
+	}, nil
+}
+
+// Sign returns a signed state embedding st. Every state contains a random
+// value, so that two states with the same content are different.
+func (s *StateSigner) Sign(st *State) (string, error) {
+	id, err := randomString(16)
+	if err != nil {
+		return "", fmt.Errorf("generate state ID: %v", err)
+	}
+
+	expiresAt := st.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(s.lifetime)
+	}
+
+	payload, err := json.Marshal(&statePayload{
+		ID:        id,
+		ReturnURL: st.ReturnURL,
+		Nonce:     st.Nonce,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode state: %v", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + s.signature(s.keys[0], encoded), nil
+}
+
+// Verify checks the signature and the expiration of a signed state, and
+// returns its content.
+func (s *StateSigner) Verify(state string) (*State, error) {
+	encoded, signature, ok := strings.Cut(state, ".")
+	if !ok {
+		return nil, fmt.Errorf("invalid state format")
+	}
+
+	valid := false
+
+	for _, key := range s.keys {
+		if hmac.Equal([]byte(signature), []byte(s.signature(key, encoded))) {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		return nil, fmt.Errorf("invalid state signature")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode state: %v", err)
+	}
+
+	var payload statePayload
+
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("decode state: %v", err)
+	}
+
+	expiresAt := time.Unix(payload.ExpiresAt, 0)
+
+	if !time.Now().Before(expiresAt) {
+		return nil, fmt.Errorf("state expired at %v", expiresAt.UTC().Format(time.RFC3339))
+	}
+
+	return &State{
+		ReturnURL: payload.ReturnURL,
+		Nonce:     payload.Nonce,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *StateSigner) signature(key []byte, encoded string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func newStateSigner(t *testing.T, keys ...[]byte) *ims.StateSigner {
+	t.Helper()
+
+	s, err := ims.NewStateSigner(&ims.StateSignerConfig{Keys: keys})
+	if err != nil {
+		t.Fatalf("create signer: %v", err)
+	}
+
+	return s
+}
+
+func TestStateSigner(t *testing.T) {
+	s := newStateSigner(t, bytes.Repeat([]byte("k"), 32))
+
+	state, err := s.Sign(&ims.State{
+		ReturnURL: "/dashboard?tab=1",
+		Nonce:     "nonce",
+	})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	other, err := s.Sign(&ims.State{ReturnURL: "/dashboard?tab=1", Nonce: "nonce"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if other == state {
+		t.Fatalf("states not unique")
+	}
+
+	st, err := s.Verify(state)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if st.ReturnURL != "/dashboard?tab=1" {
+		t.Fatalf("invalid return URL: %v", st.ReturnURL)
+	}
+	if st.Nonce != "nonce" {
+		t.Fatalf("invalid nonce: %v", st.Nonce)
+	}
+	if d := time.Until(st.ExpiresAt); d <= 9*time.Minute || d > 10*time.Minute {
+		t.Fatalf("invalid expiration: %v", st.ExpiresAt)
+	}
+}
+
+func TestStateSignerKeyRotation(t *testing.T) {
+	var (
+		oldKey = bytes.Repeat([]byte("o"), 32)
+		newKey = bytes.Repeat([]byte("n"), 32)
+	)
+
+	state, err := newStateSigner(t, oldKey).Sign(&ims.State{})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	if _, err := newStateSigner(t, newKey, oldKey).Verify(state); err != nil {
+		t.Fatalf("verify with rotated keys: %v", err)
+	}
+
+	if _, err := newStateSigner(t, newKey).Verify(state); err == nil || err.Error() != "invalid state signature" {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestStateSignerInvalidState(t *testing.T) {
+	s := newStateSigner(t, bytes.Repeat([]byte("k"), 32))
+
+	expired, err := s.Sign(&ims.State{ExpiresAt: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	valid, err := s.Sign(&ims.State{ReturnURL: "/"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	payload, signature, _ := strings.Cut(valid, ".")
+
+	tests := []struct {
+		name    string
+		state   string
+		wantErr string
+	}{
+		{
+			name:    "expired",
+			state:   expired,
+			wantErr: "state expired at",
+		},
+		{
+			name:    "malformed",
+			state:   "malformed",
+			wantErr: "invalid state format",
+		},
+		{
+			name:    "tampered",
+			state:   payload + "x." + signature,
+			wantErr: "invalid state signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Verify(tt.state); err == nil {
+				t.Fatalf("expected error")
+			} else if !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewStateSignerInvalidConfig(t *testing.T) {
+	if _, err := ims.NewStateSigner(&ims.StateSignerConfig{}); err == nil {
+		t.Fatalf("expected error for missing keys")
+	}
+
+	if _, err := ims.NewStateSigner(&ims.StateSignerConfig{Keys: [][]byte{[]byte("short")}}); err == nil {
+		t.Fatalf("expected error for short key")
+	}
+}
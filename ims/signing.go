@@ -0,0 +1,167 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signerMethod is a jwt.SigningMethod that delegates the signature to a
+// crypto.Signer. Unlike the signing methods provided by the jwt package, it
+// doesn't need access to the private key material, which might live in a
+// hardware module or in a remote agent.
+type signerMethod struct {
+	alg  string
+	hash crypto.Hash
+	pss  bool
+	// curve is set for ECDSA algorithms only.
+	curve elliptic.Curve
+}
+
+var signerMethods = map[string]*signerMethod{
+	"RS256": {alg: "RS256", hash: crypto.SHA256},
+	"RS384": {alg: "RS384", hash: crypto.SHA384},
+	"RS512": {alg: "RS512", hash: crypto.SHA512},
+	"PS256": {alg: "PS256", hash: crypto.SHA256, pss: true},
+	"PS384": {alg: "PS384", hash: crypto.SHA384, pss: true},
+	"PS512": {alg: "PS512", hash: crypto.SHA512, pss: true},
+	"ES256": {alg: "ES256", hash: crypto.SHA256, curve: elliptic.P256()},
+	"ES384": {alg: "ES384", hash: crypto.SHA384, curve: elliptic.P384()},
+	"ES512": {alg: "ES512", hash: crypto.SHA512, curve: elliptic.P521()},
+}
+
+// signingMethodFor returns the signing method for the given algorithm and
+// verifies that it is compatible with the public key of the signer. If alg is
+// empty, RS256 is used for RSA keys and the ES algorithm matching the curve
+// is used for ECDSA keys.
+func signingMethodFor(alg string, signer crypto.Signer) (*signerMethod, error) {
+	if signer == nil {
+		return nil, fmt.Errorf("missing signer")
+	}
+
+	pub := signer.Public()
+
+	if alg == "" {
+		switch k := pub.(type) {
+		case *rsa.PublicKey:
+			alg = "RS256"
+		case *ecdsa.PublicKey:
+			for _, m := range signerMethods {
+				if m.curve != nil && m.curve == k.Curve {
+					alg = m.alg
+				}
+			}
+		}
+	}
+
+	m, ok := signerMethods[alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm: %q", alg)
+	}
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if m.curve != nil {
+			return nil, fmt.Errorf("signing algorithm %v requires an ECDSA key", m.alg)
+		}
+	case *ecdsa.PublicKey:
+		if m.curve == nil {
+			return nil, fmt.Errorf("signing algorithm %v requires an RSA key", m.alg)
+		}
+		if m.curve != k.Curve {
+			return nil, fmt.Errorf("signing algorithm %v doesn't match the key curve", m.alg)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type: %T", pub)
+	}
+
+	return m, nil
+}
+
+func (m *signerMethod) Alg() string {
+	return m.alg
+}
+
+func (m *signerMethod) Verify(string, []byte, interface{}) error {
+	return fmt.Errorf("verification not supported")
+}
+
+func (m *signerMethod) Sign(signingString string, key interface{}) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("invalid key type: %T", key)
+	}
+
+	h := m.hash.New()
+	h.Write([]byte(signingString))
+	digest := h.Sum(nil)
+
+	var opts crypto.SignerOpts = m.hash
+	if m.pss {
+		opts = &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       m.hash,
+		}
+	}
+
+	sig, err := signer.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.curve == nil {
+		return sig, nil
+	}
+
+	// crypto.Signer returns ECDSA signatures as ASN.1 sequences, while JWS
+	// requires the concatenation of the fixed-size R and S values.
+
+	var parsed struct {
+		R, S *big.Int
+	}
+
+	if _, err := asn1.Unmarshal(sig, &parsed); err != nil {
+		return nil, fmt.Errorf("decode ECDSA signature: %v", err)
+	}
+
+	size := (m.curve.Params().BitSize + 7) / 8
+	out := make([]byte, 2*size)
+	parsed.R.FillBytes(out[:size])
+	parsed.S.FillBytes(out[size:])
+
+	return out, nil
+}
+
+// signJWT signs the claims with the given signer. The key ID is added to the
+// header of the token, if not empty.
+func signJWT(claims jwt.Claims, signer crypto.Signer, alg, keyID string) (string, error) {
+	method, err := signingMethodFor(alg, signer)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+
+	return token.SignedString(signer)
+}
@@ -42,6 +42,10 @@ type TokenRequest struct {
 	OrgID string
 	// Resources provided to be added as access token audiences
 	Resource []string
+	// ClientAuth is the method used to authenticate the client. If not
+	// provided, ClientSecret is sent in the body of the request. It can't be
+	// used together with ClientSecret.
+	ClientAuth ClientAuthenticator
 }
 
 // TokenResponse is the response returned after an access token request.
@@ -55,6 +59,8 @@ type TokenResponse struct {
 	ExpiresIn time.Duration
 	// User id received from IMS token
 	UserID string
+	// IDToken is the ID token, returned if the openid scope was requested.
+	IDToken string
 }
 
 // TokenWithContext requests an access token.
@@ -67,7 +73,7 @@ func (c *Client) TokenWithContext(ctx context.Context, r *TokenRequest) (*TokenR
 		return nil, fmt.Errorf("missing client ID")
 	}
 
-	if r.ClientSecret == "" && r.CodeVerifier == "" {
+	if r.ClientSecret == "" && r.CodeVerifier == "" && r.ClientAuth == nil {
 		return nil, fmt.Errorf("missing either client secret or code verifier")
 	}
 
@@ -83,11 +89,6 @@ func (c *Client) TokenWithContext(ctx context.Context, r *TokenRequest) (*TokenR
 	}
 	data.Set("client_id", r.ClientID)
 
-	// client secret is optional, IMS supports public clients
-	if r.ClientSecret != "" {
-		data.Set("client_secret", r.ClientSecret)
-	}
-
 	if r.CodeVerifier != "" {
 		data.Set("code_verifier", r.CodeVerifier)
 	}
@@ -105,11 +106,20 @@ func (c *Client) TokenWithContext(ctx context.Context, r *TokenRequest) (*TokenR
 		data.Add("resource", res)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/ims/token/v2", c.url), strings.NewReader(data.Encode()))
+	tokenURL := fmt.Sprintf("%s/ims/token/v2", c.url)
+
+	// client secret is optional, IMS supports public clients
+	header, err := authenticateClient(r.ClientAuth, r.ClientID, r.ClientSecret, tokenURL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %v", err)
 	}
 
+	req.Header = header
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	res, err := c.do(req)
@@ -126,6 +136,7 @@ func (c *Client) TokenWithContext(ctx context.Context, r *TokenRequest) (*TokenR
 		RefreshToken string `json:"refresh_token"`
 		ExpiresIn    int    `json:"expires_in"`
 		UserID       string `json:"userId"`
+		IDToken      string `json:"id_token"`
 	}
 
 	if err := json.Unmarshal(res.Body, &payload); err != nil {
@@ -138,6 +149,7 @@ func (c *Client) TokenWithContext(ctx context.Context, r *TokenRequest) (*TokenR
 		RefreshToken: payload.RefreshToken,
 		ExpiresIn:    time.Second * time.Duration(payload.ExpiresIn),
 		UserID:       payload.UserID,
+		IDToken:      payload.IDToken,
 	}, nil
 }
 
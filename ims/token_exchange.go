@@ -0,0 +1,194 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenExchangePath      = "/ims/token/v4"
+)
+
+// Token type identifiers used in token exchange requests (RFC 8693, section
+// 3).
+const (
+	TokenTypeURIAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeURIRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeURIIDToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeURIJWT          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// TokenExchangeRequest is the request for a token exchange (RFC 8693).
+type TokenExchangeRequest struct {
+	// ClientID is the client ID. This field is required.
+	ClientID string
+	// ClientSecret is the client secret.
+	ClientSecret string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ClientAuthenticator
+	// SubjectToken is the token representing the identity of the party on
+	// behalf of whom the request is made. This field is required.
+	SubjectToken string
+	// SubjectTokenType is the type of SubjectToken, e.g.
+	// TokenTypeURIIDToken. If not provided, TokenTypeURIAccessToken is used.
+	SubjectTokenType string
+	// ActorToken is the token representing the identity of the acting
+	// party. Optional.
+	ActorToken string
+	// ActorTokenType is the type of ActorToken. It is required if ActorToken
+	// is provided, and must be empty otherwise.
+	ActorTokenType string
+	// RequestedTokenType is the type of the requested token. Optional.
+	RequestedTokenType string
+	// Audience is the logical name of the target services. Optional.
+	Audience []string
+	// Resource is the URI of the target services. Optional.
+	Resource []string
+	// Scopes is the list of scopes of the requested token. Optional.
+	Scopes []string
+	// Path is the path of the token endpoint, relative to the URL of the
+	// client. If not provided, it defaults to "/ims/token/v4".
+	Path string
+}
+
+// TokenExchangeResponse is the response of a token exchange.
+type TokenExchangeResponse struct {
+	Response
+	// AccessToken is the issued token. Despite the name, it isn't
+	// necessarily an access token: see IssuedTokenType.
+	AccessToken string
+	// IssuedTokenType is the type of AccessToken.
+	IssuedTokenType string
+	// TokenType is how the issued token can be used, e.g. Bearer.
+	TokenType string
+	// RefreshToken is the refresh token, if issued.
+	RefreshToken string
+	// ExpiresIn is the lifetime of the issued token.
+	ExpiresIn time.Duration
+}
+
+func validateTokenExchangeRequest(r *TokenExchangeRequest) error {
+	switch {
+	case r.ClientID == "":
+		return fmt.Errorf("missing client ID parameter")
+	case r.SubjectToken == "":
+		return fmt.Errorf("missing subject token parameter")
+	case r.ActorToken != "" && r.ActorTokenType == "":
+		return fmt.Errorf("missing actor token type parameter")
+	case r.ActorToken == "" && r.ActorTokenType != "":
+		return fmt.Errorf("actor token type provided without actor token")
+	case r.Path != "" && !strings.HasPrefix(r.Path, "/"):
+		return fmt.Errorf("invalid path: %v", r.Path)
+	default:
+		return nil
+	}
+}
+
+// TokenExchangeWithContext exchanges a token for another token.
+func (c *Client) TokenExchangeWithContext(ctx context.Context, r *TokenExchangeRequest) (*TokenExchangeResponse, error) {
+	if err := validateTokenExchangeRequest(r); err != nil {
+		return nil, fmt.Errorf("invalid parameters for token exchange: %v", err)
+	}
+
+	subjectTokenType := r.SubjectTokenType
+	if subjectTokenType == "" {
+		subjectTokenType = TokenTypeURIAccessToken
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", tokenExchangeGrantType)
+	data.Set("client_id", r.ClientID)
+	data.Set("subject_token", r.SubjectToken)
+	data.Set("subject_token_type", subjectTokenType)
+
+	if r.ActorToken != "" {
+		data.Set("actor_token", r.ActorToken)
+		data.Set("actor_token_type", r.ActorTokenType)
+	}
+
+	if r.RequestedTokenType != "" {
+		data.Set("requested_token_type", r.RequestedTokenType)
+	}
+
+	for _, aud := range r.Audience {
+		data.Add("audience", aud)
+	}
+
+	for _, res := range r.Resource {
+		data.Add("resource", res)
+	}
+
+	if len(r.Scopes) > 0 {
+		data.Set("scope", strings.Join(r.Scopes, ","))
+	}
+
+	path := r.Path
+	if path == "" {
+		path = tokenExchangePath
+	}
+
+	tokenURL := c.url + path
+	header, err := authenticateClient(r.ClientAuth, r.ClientID, r.ClientSecret, tokenURL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errorResponse(res)
+	}
+
+	var body struct {
+		AccessToken     string `json:"access_token"`
+		IssuedTokenType string `json:"issued_token_type"`
+		TokenType       string `json:"token_type"`
+		RefreshToken    string `json:"refresh_token"`
+		ExpiresIn       int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(res.Body, &body); err != nil {
+		return nil, fmt.Errorf("decode response: %v", err)
+	}
+
+	return &TokenExchangeResponse{
+		Response:        *res,
+		AccessToken:     body.AccessToken,
+		IssuedTokenType: body.IssuedTokenType,
+		TokenType:       body.TokenType,
+		RefreshToken:    body.RefreshToken,
+		ExpiresIn:       time.Second * time.Duration(body.ExpiresIn),
+	}, nil
+}
+
+// TokenExchange is equivalent to TokenExchangeWithContext with a background
+// context.
+func (c *Client) TokenExchange(r *TokenExchangeRequest) (*TokenExchangeResponse, error) {
+	return c.TokenExchangeWithContext(context.Background(), r)
+}
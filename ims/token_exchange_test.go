@@ -0,0 +1,214 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestTokenExchange(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ims/token/v4" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		want := map[string][]string{
+			"grant_type":           {"urn:ietf:params:oauth:grant-type:token-exchange"},
+			"client_id":            {"client-id"},
+			"client_secret":        {"client-secret"},
+			"subject_token":        {"id-token"},
+			"subject_token_type":   {ims.TokenTypeURIIDToken},
+			"actor_token":          {"actor-token"},
+			"actor_token_type":     {ims.TokenTypeURIJWT},
+			"requested_token_type": {ims.TokenTypeURIRefreshToken},
+			"audience":             {"service-a", "service-b"},
+			"resource":             {"https://a.example.com"},
+			"scope":                {"openid,profile"},
+		}
+
+		for k, v := range want {
+			if got := r.PostForm[k]; !reflect.DeepEqual(got, v) {
+				t.Fatalf("invalid %v: %v", k, got)
+			}
+		}
+
+		_, _ = w.Write([]byte(`{
+			"access_token": "refresh-token",
+			"issued_token_type": "urn:ietf:params:oauth:token-type:refresh_token",
+			"token_type": "N_A",
+			"expires_in": 3600
+		}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	r, err := c.TokenExchange(&ims.TokenExchangeRequest{
+		ClientID:           "client-id",
+		ClientSecret:       "client-secret",
+		SubjectToken:       "id-token",
+		SubjectTokenType:   ims.TokenTypeURIIDToken,
+		ActorToken:         "actor-token",
+		ActorTokenType:     ims.TokenTypeURIJWT,
+		RequestedTokenType: ims.TokenTypeURIRefreshToken,
+		Audience:           []string{"service-a", "service-b"},
+		Resource:           []string{"https://a.example.com"},
+		Scopes:             []string{"openid", "profile"},
+	})
+	if err != nil {
+		t.Fatalf("exchange token: %v", err)
+	}
+	if r.AccessToken != "refresh-token" {
+		t.Fatalf("invalid access token: %v", r.AccessToken)
+	}
+	if r.IssuedTokenType != ims.TokenTypeURIRefreshToken {
+		t.Fatalf("invalid issued token type: %v", r.IssuedTokenType)
+	}
+	if r.TokenType != "N_A" {
+		t.Fatalf("invalid token type: %v", r.TokenType)
+	}
+	if r.ExpiresIn != time.Hour {
+		t.Fatalf("invalid expiration: %v", r.ExpiresIn)
+	}
+}
+
+func TestTokenExchangeDefaults(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		if v := r.PostForm.Get("subject_token_type"); v != ims.TokenTypeURIAccessToken {
+			t.Fatalf("invalid subject_token_type: %v", v)
+		}
+
+		for _, k := range []string{"actor_token", "actor_token_type", "requested_token_type", "audience", "resource", "scope"} {
+			if _, ok := r.PostForm[k]; ok {
+				t.Fatalf("unexpected parameter: %v", k)
+			}
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.TokenExchange(&ims.TokenExchangeRequest{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		SubjectToken: "user-token",
+	}); err != nil {
+		t.Fatalf("exchange token: %v", err)
+	}
+}
+
+func TestTokenExchangePath(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ims/token/v3" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "expires_in": 3600}`))
+	}))
+	defer s.Close()
+
+	c, err := ims.NewClient(&ims.ClientConfig{URL: s.URL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := c.TokenExchange(&ims.TokenExchangeRequest{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		SubjectToken: "user-token",
+		Path:         "/ims/token/v3",
+	}); err != nil {
+		t.Fatalf("exchange token: %v", err)
+	}
+}
+
+func TestTokenExchangeInvalidRequest(t *testing.T) {
+	c, err := ims.NewClient(&ims.ClientConfig{URL: "http://ims.endpoint"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		request *ims.TokenExchangeRequest
+		wantErr string
+	}{
+		{
+			name:    "missing client ID",
+			request: &ims.TokenExchangeRequest{SubjectToken: "token"},
+			wantErr: "invalid parameters for token exchange: missing client ID parameter",
+		},
+		{
+			name:    "missing subject token",
+			request: &ims.TokenExchangeRequest{ClientID: "client-id"},
+			wantErr: "invalid parameters for token exchange: missing subject token parameter",
+		},
+		{
+			name: "missing actor token type",
+			request: &ims.TokenExchangeRequest{
+				ClientID:     "client-id",
+				SubjectToken: "token",
+				ActorToken:   "actor-token",
+			},
+			wantErr: "invalid parameters for token exchange: missing actor token type parameter",
+		},
+		{
+			name: "missing actor token",
+			request: &ims.TokenExchangeRequest{
+				ClientID:       "client-id",
+				SubjectToken:   "token",
+				ActorTokenType: ims.TokenTypeURIJWT,
+			},
+			wantErr: "invalid parameters for token exchange: actor token type provided without actor token",
+		},
+		{
+			name: "invalid path",
+			request: &ims.TokenExchangeRequest{
+				ClientID:     "client-id",
+				SubjectToken: "token",
+				Path:         "ims/token/v3",
+			},
+			wantErr: "invalid parameters for token exchange: invalid path: ims/token/v3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.TokenExchange(tt.request); err == nil {
+				t.Fatalf("expected error")
+			} else if err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
@@ -52,11 +52,13 @@ func TestToken(t *testing.T) {
 			RefreshToken string `json:"refresh_token"`
 			AccessToken  string `json:"access_token"`
 			UserId       string `json:"userId"`
+			IDToken      string `json:"id_token"`
 		}{
 			ExpiresIn:    3600,
 			RefreshToken: "refreshToken",
 			AccessToken:  "accessToken",
 			UserId:       "user-id",
+			IDToken:      "idToken",
 		}
 
 		if err := json.NewEncoder(w).Encode(&body); err != nil {
@@ -93,6 +95,9 @@ func TestToken(t *testing.T) {
 	if r.UserID != "user-id" {
 		t.Errorf("invalid userId: %v", r.UserID)
 	}
+	if r.IDToken != "idToken" {
+		t.Errorf("invalid ID token: %v", r.IDToken)
+	}
 }
 
 func TestTokenClientCredentials(t *testing.T) {
@@ -0,0 +1,106 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+// maxAttempts bounds the number of login attempts remembered by the server.
+// Older attempts are forgotten, and their callbacks rejected.
+const maxAttempts = 16
+
+// attempt is a single login attempt. Every attempt has its own state and code
+// verifier, so that a failed attempt can be retried from the start.
+type attempt struct {
+	state        string
+	codeVerifier string
+	// done is set once the callback of the attempt was received.
+	done bool
+}
+
+// attempts tracks the login attempts started by the redirect middleware and
+// completed by the callback middleware.
+type attempts struct {
+	usePKCE bool
+
+	mu      sync.Mutex
+	byState map[string]*attempt
+	order   []string
+}
+
+func newAttempts(usePKCE bool) *attempts {
+	return &attempts{
+		usePKCE: usePKCE,
+		byState: map[string]*attempt{},
+	}
+}
+
+// start creates a new attempt with a fresh state and code verifier.
+func (a *attempts) start() (*attempt, error) {
+	state, err := ims.NewState()
+	if err != nil {
+		return nil, fmt.Errorf("generate random state: %v", err)
+	}
+
+	at := &attempt{
+		state: state,
+	}
+
+	if a.usePKCE {
+		pkce, err := ims.NewPKCE()
+		if err != nil {
+			return nil, fmt.Errorf("generate random code verifier: %v", err)
+		}
+		at.codeVerifier = pkce.CodeVerifier
+	}
+
+	a.add(at)
+
+	return at, nil
+}
+
+func (a *attempts) add(at *attempt) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.order) == maxAttempts {
+		delete(a.byState, a.order[0])
+		a.order = a.order[1:]
+	}
+
+	a.byState[at.state] = at
+	a.order = append(a.order, at.state)
+}
+
+// claim marks the attempt with the given state as done and returns it. It
+// returns nil if there is no such attempt, and false if the attempt was
+// already done.
+func (a *attempts) claim(state string) (*attempt, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	at, ok := a.byState[state]
+	if !ok {
+		return nil, false
+	}
+
+	if at.done {
+		return at, false
+	}
+
+	at.done = true
+
+	return at, true
+}
@@ -13,6 +13,7 @@ package login
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -23,51 +24,84 @@ type callbackBackend interface {
 
 type callbackMiddleware struct {
 	client       callbackBackend
-	state        string
+	attempts     *attempts
 	clientID     string
 	clientSecret string
+	clientAuth   ims.ClientAuthenticator
 	scope        []string
 	next         http.Handler
-	codeVerifier string
+	// retry serves the callbacks of attempts already done.
+	retry http.Handler
+}
+
+func newCallbackMiddleware(cfg *ServerConfig, attempts *attempts, next, retry http.Handler) *callbackMiddleware {
+	return &callbackMiddleware{
+		client:       cfg.Client,
+		clientID:     cfg.ClientID,
+		clientSecret: cfg.ClientSecret,
+		clientAuth:   cfg.ClientAuth,
+		scope:        cfg.Scope,
+		attempts:     attempts,
+		next:         next,
+		retry:        retry,
+	}
 }
 
 func (h *callbackMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	values := r.URL.Query()
 
-	urlErr := values.Get("error")
-	if urlErr != "" {
-		serveError(h.next, w, r, fmt.Errorf("backend error: %s", urlErr))
+	state := values.Get("state")
+
+	// A callback for an attempt already done, e.g. after a reload of the
+	// page, produces no outcome.
+	at, ok := h.attempts.claim(state)
+	if at != nil && !ok {
+		h.retry.ServeHTTP(w, r)
 		return
 	}
 
-	state := values.Get("state")
-	if state == "" {
-		serveError(h.next, w, r, fmt.Errorf("missing state parameter"))
+	res, err := h.exchange(values, at)
+	if err != nil {
+		serveError(h.next, w, r, err)
 		return
 	}
 
-	if h.state != state {
-		serveError(h.next, w, r, fmt.Errorf("invalid state parameter"))
-		return
+	serveResult(h.next, w, r, res)
+}
+
+// exchange verifies the parameters of the callback of the attempt, which is
+// nil if the state is unknown, and exchanges the authorization code. The state
+// is checked first, so that an error parameter is only reported for a
+// callback of a known attempt, and not for a request forged by a third party.
+func (h *callbackMiddleware) exchange(values url.Values, at *attempt) (*ims.TokenResponse, error) {
+	if values.Get("state") == "" {
+		return nil, fmt.Errorf("missing state parameter")
+	}
+
+	if at == nil {
+		return nil, fmt.Errorf("invalid state parameter")
+	}
+
+	if urlErr := values.Get("error"); urlErr != "" {
+		return nil, fmt.Errorf("backend error: %s", urlErr)
 	}
 
 	code := values.Get("code")
 	if code == "" {
-		serveError(h.next, w, r, fmt.Errorf("missing code parameter"))
-		return
+		return nil, fmt.Errorf("missing code parameter")
 	}
 
 	res, err := h.client.Token(&ims.TokenRequest{
 		Code:         code,
 		ClientID:     h.clientID,
 		ClientSecret: h.clientSecret,
+		ClientAuth:   h.clientAuth,
 		Scope:        h.scope,
-		CodeVerifier: h.codeVerifier,
+		CodeVerifier: at.codeVerifier,
 	})
 	if err != nil {
-		serveError(h.next, w, r, fmt.Errorf("obtaining access token: %v", err))
-		return
+		return nil, fmt.Errorf("obtaining access token: %w", err)
 	}
 
-	serveResult(h.next, w, r, res)
+	return res, nil
 }
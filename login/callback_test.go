@@ -41,9 +41,20 @@ func urlWithParams(path string, params map[string]string) string {
 	return u.String()
 }
 
+// testAttempts returns attempts started with the given states.
+func testAttempts(states ...string) *attempts {
+	a := newAttempts(false)
+
+	for _, state := range states {
+		a.add(&attempt{state: state})
+	}
+
+	return a
+}
+
 func TestCallback(t *testing.T) {
 	m := &callbackMiddleware{
-		state:        "state",
+		attempts:     testAttempts("state"),
 		clientID:     "client-id",
 		clientSecret: "client-secret",
 		scope:        []string{"a", "b"},
@@ -85,6 +96,7 @@ func TestCallback(t *testing.T) {
 
 func TestCallbackError(t *testing.T) {
 	m := &callbackMiddleware{
+		attempts: testAttempts("state"),
 		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			err, ok := r.Context().Value(contextKeyError).(error)
 			if !ok {
@@ -101,6 +113,35 @@ func TestCallbackError(t *testing.T) {
 
 	target := urlWithParams("/", map[string]string{
 		"error": "error",
+		"state": "state",
+	})
+
+	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+func TestCallbackErrorInvalidState(t *testing.T) {
+	m := &callbackMiddleware{
+		attempts: testAttempts("state"),
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err, ok := r.Context().Value(contextKeyError).(error)
+			if !ok {
+				t.Fatalf("invalid context value")
+			}
+			if err == nil {
+				t.Fatalf("no error returned")
+			}
+			if err.Error() != "invalid state parameter" {
+				t.Fatalf("invalid error: %v", err)
+			}
+		}),
+	}
+
+	// An error without the state of an attempt is not reported as the
+	// outcome of the login.
+
+	target := urlWithParams("/", map[string]string{
+		"error": "access_denied",
+		"state": "invalid-state",
 	})
 
 	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, target, nil))
@@ -108,6 +149,7 @@ func TestCallbackError(t *testing.T) {
 
 func TestCallbackNoState(t *testing.T) {
 	m := &callbackMiddleware{
+		attempts: testAttempts("state"),
 		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			err, ok := r.Context().Value(contextKeyError).(error)
 			if !ok {
@@ -127,7 +169,7 @@ func TestCallbackNoState(t *testing.T) {
 
 func TestCallbackInvalidState(t *testing.T) {
 	m := &callbackMiddleware{
-		state: "state",
+		attempts: testAttempts("state"),
 		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			err, ok := r.Context().Value(contextKeyError).(error)
 			if !ok {
@@ -151,7 +193,7 @@ func TestCallbackInvalidState(t *testing.T) {
 
 func TestCallbackMissingCode(t *testing.T) {
 	m := &callbackMiddleware{
-		state: "state",
+		attempts: testAttempts("state"),
 		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			err, ok := r.Context().Value(contextKeyError).(error)
 			if !ok {
@@ -175,7 +217,7 @@ func TestCallbackMissingCode(t *testing.T) {
 
 func TestCallbackBackendError(t *testing.T) {
 	m := &callbackMiddleware{
-		state: "state",
+		attempts: testAttempts("state"),
 		client: testCallbackBackend(func(r *ims.TokenRequest) (*ims.TokenResponse, error) {
 			return nil, fmt.Errorf("error")
 		}),
@@ -200,3 +242,38 @@ func TestCallbackBackendError(t *testing.T) {
 
 	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, target, nil))
 }
+
+func TestCallbackAttemptDone(t *testing.T) {
+	var (
+		calls int
+		retry bool
+	)
+
+	m := &callbackMiddleware{
+		attempts: testAttempts("state"),
+		client: testCallbackBackend(func(r *ims.TokenRequest) (*ims.TokenResponse, error) {
+			return &ims.TokenResponse{}, nil
+		}),
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}),
+		retry: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			retry = true
+		}),
+	}
+
+	target := urlWithParams("/", map[string]string{
+		"state": "state",
+		"code":  "code",
+	})
+
+	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, target, nil))
+	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if calls != 1 {
+		t.Fatalf("invalid number of outcomes: %v", calls)
+	}
+	if !retry {
+		t.Fatalf("retry handler not invoked")
+	}
+}
@@ -31,3 +31,17 @@ func serveError(h http.Handler, w http.ResponseWriter, r *http.Request, err erro
 func serveResult(h http.Handler, w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) {
 	h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKeyResult, res)))
 }
+
+// ResultFromContext returns the response of a successful login. It is meant
+// to be called by ServerConfig.OnSuccess.
+func ResultFromContext(ctx context.Context) (*ims.TokenResponse, bool) {
+	res, ok := ctx.Value(contextKeyResult).(*ims.TokenResponse)
+	return res, ok
+}
+
+// ErrorFromContext returns the error of a failed login, or nil if there is
+// none. It is meant to be called by ServerConfig.OnError.
+func ErrorFromContext(ctx context.Context) error {
+	err, _ := ctx.Value(contextKeyError).(error)
+	return err
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+// runHeadless performs an out-of-band login. The authorization code pasted by
+// the user is verified and exchanged by the same middlewares used by Server.
+func runHeadless(ctx context.Context, cfg *RunConfig, timeout time.Duration) (*ims.TokenResponse, error) {
+	if cfg.RedirectURI != "" {
+		if err := ims.ValidateRedirectURI(cfg.RedirectURI, nil); err != nil {
+			return nil, &RunError{Op: RunOpCreateServer, Err: err}
+		}
+	}
+
+	input := cfg.Input
+	if input == nil {
+		input = os.Stdin
+	}
+
+	output := cfg.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	attempts := newAttempts(true)
+
+	started, authorizeURL, err := newRedirectMiddleware(&cfg.ServerConfig, attempts, nil).startAttempt(ctx)
+	if err != nil {
+		return nil, &RunError{Op: RunOpLogin, Err: err}
+	}
+
+	_, _ = fmt.Fprintf(output, "Open the following URL in a browser to log in:\n\n    %s\n\nThen paste the URL of the page the browser was redirected to, or the code in it: ", authorizeURL)
+
+	// The read can't be interrupted, so the goroutine is abandoned if the
+	// context is done first.
+	type line struct {
+		text string
+		err  error
+	}
+
+	lineCh := make(chan line, 1)
+
+	go func() {
+		text, err := bufio.NewReader(input).ReadString('\n')
+		if err == io.EOF && text != "" {
+			err = nil
+		}
+		lineCh <- line{text, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var text string
+
+	select {
+	case l := <-lineCh:
+		if l.err != nil {
+			return nil, &RunError{Op: RunOpReadInput, Err: l.err}
+		}
+		text = l.text
+	case <-timer.C:
+		return nil, &RunError{Op: RunOpWait, Err: ErrTimeout}
+	case <-ctx.Done():
+		return nil, &RunError{Op: RunOpWait, Err: ctx.Err()}
+	}
+
+	values, err := parseHeadlessInput(text, started.state)
+	if err != nil {
+		return nil, &RunError{Op: RunOpReadInput, Err: err}
+	}
+
+	at, _ := attempts.claim(values.Get("state"))
+
+	res, err := newCallbackMiddleware(&cfg.ServerConfig, attempts, nil, nil).exchange(values, at)
+	if err != nil {
+		return nil, &RunError{Op: RunOpLogin, Err: err}
+	}
+
+	return res, nil
+}
+
+// parseHeadlessInput returns the parameters of the callback from the input of
+// the user. A raw code carries no state, so it is bound to the state of the
+// attempt, and only verified through PKCE.
+func parseHeadlessInput(input, state string) (url.Values, error) {
+	input = strings.TrimSpace(input)
+
+	if input == "" {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	if !strings.ContainsAny(input, "?#=") {
+		return url.Values{"code": {input}, "state": {state}}, nil
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("parse redirected URL: %v", err)
+	}
+
+	values := u.Query()
+
+	// The parameters are in the fragment with the fragment response mode.
+	if values.Get("code") == "" && values.Get("error") == "" && u.Fragment != "" {
+		if values, err = url.ParseQuery(u.Fragment); err != nil {
+			return nil, fmt.Errorf("parse redirected URL fragment: %v", err)
+		}
+	}
+
+	if values.Get("code") == "" && values.Get("error") == "" {
+		return nil, fmt.Errorf("no code in redirected URL")
+	}
+
+	return values, nil
+}
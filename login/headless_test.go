@@ -0,0 +1,233 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/adobe/ims-go/login"
+)
+
+// outputWriter sends everything written to it on a channel.
+type outputWriter chan string
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+var authorizeURLPattern = regexp.MustCompile(`http\S+/ims/authorize/v1\S+`)
+
+// headlessTokenServer returns a fake IMS token endpoint verifying the code
+// verifier against the code challenge stored in challenge.
+func headlessTokenServer(t *testing.T, challenge *string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		if r.PostForm.Get("code") != "code" {
+			t.Fatalf("invalid code: %v", r.PostForm.Get("code"))
+		}
+		if ims.CodeChallenge(r.PostForm.Get("code_verifier")) != *challenge {
+			t.Fatalf("invalid code verifier")
+		}
+
+		_, _ = fmt.Fprint(w, `{"access_token": "access-token", "expires_in": 3600}`)
+	}))
+}
+
+// runHeadless runs a headless login, and answers the printed authorization
+// URL with the input returned by paste.
+func runHeadless(t *testing.T, paste func(authorizeURL *url.URL) string) (*ims.TokenResponse, error) {
+	t.Helper()
+
+	var challenge string
+
+	s := headlessTokenServer(t, &challenge)
+	defer s.Close()
+
+	cfg := newRunConfig(t, s.URL, func(string) error {
+		t.Fatalf("browser opened in headless mode")
+		return nil
+	})
+
+	inputReader, inputWriter := io.Pipe()
+	output := make(outputWriter, 1)
+
+	cfg.RedirectURI = "https://app.example.com/callback"
+	cfg.Headless = true
+	cfg.Input = inputReader
+	cfg.Output = output
+	cfg.Timeout = 5 * time.Second
+
+	go func() {
+		match := authorizeURLPattern.FindString(<-output)
+		if match == "" {
+			t.Errorf("authorization URL not printed")
+			return
+		}
+
+		u, err := url.Parse(match)
+		if err != nil {
+			t.Errorf("parse authorization URL: %v", err)
+			return
+		}
+
+		challenge = u.Query().Get("code_challenge")
+
+		_, _ = io.WriteString(inputWriter, paste(u)+"\n")
+	}()
+
+	return login.Run(context.Background(), cfg)
+}
+
+func TestRunHeadless(t *testing.T) {
+	tests := []struct {
+		name  string
+		paste func(u *url.URL) string
+	}{
+		{
+			name: "redirected URL",
+			paste: func(u *url.URL) string {
+				return "https://app.example.com/callback?code=code&state=" + url.QueryEscape(u.Query().Get("state"))
+			},
+		},
+		{
+			name: "redirected URL with fragment",
+			paste: func(u *url.URL) string {
+				return "https://app.example.com/callback#code=code&state=" + url.QueryEscape(u.Query().Get("state"))
+			},
+		},
+		{
+			name: "raw code",
+			paste: func(u *url.URL) string {
+				return "  code  "
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := runHeadless(t, tt.paste)
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+
+			if res.AccessToken != "access-token" {
+				t.Fatalf("invalid access token: %v", res.AccessToken)
+			}
+		})
+	}
+}
+
+func TestRunHeadlessErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		paste   func(u *url.URL) string
+		wantOp  login.RunOp
+		wantErr string
+	}{
+		{
+			name: "invalid state",
+			paste: func(u *url.URL) string {
+				return "https://app.example.com/callback?code=code&state=other"
+			},
+			wantOp:  login.RunOpLogin,
+			wantErr: "invalid state parameter",
+		},
+		{
+			name: "backend error",
+			paste: func(u *url.URL) string {
+				return "https://app.example.com/callback?error=access_denied&state=" + url.QueryEscape(u.Query().Get("state"))
+			},
+			wantOp:  login.RunOpLogin,
+			wantErr: "backend error: access_denied",
+		},
+		{
+			name: "URL without code",
+			paste: func(u *url.URL) string {
+				return "https://app.example.com/callback?foo=bar"
+			},
+			wantOp:  login.RunOpReadInput,
+			wantErr: "no code in redirected URL",
+		},
+		{
+			name: "empty input",
+			paste: func(u *url.URL) string {
+				return ""
+			},
+			wantOp:  login.RunOpReadInput,
+			wantErr: "empty input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runHeadless(t, tt.paste)
+
+			var runErr *login.RunError
+			if !errors.As(err, &runErr) || runErr.Op != tt.wantOp || runErr.Err.Error() != tt.wantErr {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunHeadlessInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *login.RunConfig)
+	}{
+		{
+			name:   "ports",
+			modify: func(cfg *login.RunConfig) { cfg.Ports = []int{8080} },
+		},
+		{
+			name:   "redirect URI pattern",
+			modify: func(cfg *login.RunConfig) { cfg.RedirectURIPattern = "http://localhost:{8080}/callback" },
+		},
+		{
+			name:   "address",
+			modify: func(cfg *login.RunConfig) { cfg.Addr = "127.0.0.1:8080" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newRunConfig(t, "http://127.0.0.1", func(string) error {
+				t.Fatalf("browser opened in headless mode")
+				return nil
+			})
+			cfg.Headless = true
+			tt.modify(cfg)
+
+			_, err := login.Run(context.Background(), cfg)
+
+			var runErr *login.RunError
+			if !errors.As(err, &runErr) || runErr.Op != login.RunOpCreateServer {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+// maxPatternPorts bounds the number of ports in a redirect URI pattern.
+const maxPatternPorts = 1000
+
+// ErrPortsBusy is the error wrapped by Listen when none of the allowed ports
+// is free.
+var ErrPortsBusy = errors.New("all allowed ports are busy")
+
+// ListenConfig is the configuration for Listen. Either Ports or
+// RedirectURIPattern is required.
+type ListenConfig struct {
+	// Ports are the ports of the redirect URIs registered in IMS, tried in
+	// order. The redirect URI is derived as http://127.0.0.1:<port>/.
+	Ports []int
+	// RedirectURIPattern is a redirect URI registered in IMS, whose port is
+	// a comma-separated list of ports and port ranges in braces, e.g.
+	// "http://localhost:{8000-8002,9000}/callback". The ports are tried in
+	// order. The host must be a loopback host. For "localhost", Listen binds
+	// 127.0.0.1, instead of whichever address "localhost" resolves to first,
+	// and the redirect URI keeps "localhost". If provided, Ports must be
+	// empty.
+	RedirectURIPattern string
+}
+
+// Listen listens on the first free port allowed by the configuration, and
+// returns the listener with the redirect URI pointing to it, to be used as
+// ServerConfig.RedirectURI. If every allowed port is busy, the error wraps
+// ErrPortsBusy.
+func Listen(cfg *ListenConfig) (net.Listener, string, error) {
+	var (
+		prefix = "http://127.0.0.1:"
+		suffix = "/"
+		ports  = cfg.Ports
+	)
+
+	switch {
+	case cfg.RedirectURIPattern != "" && len(cfg.Ports) > 0:
+		return nil, "", fmt.Errorf("ports and redirect URI pattern are mutually exclusive")
+	case cfg.RedirectURIPattern != "":
+		var err error
+
+		if prefix, suffix, ports, err = parseRedirectURIPattern(cfg.RedirectURIPattern); err != nil {
+			return nil, "", err
+		}
+	case len(cfg.Ports) == 0:
+		return nil, "", fmt.Errorf("missing ports")
+	}
+
+	var lastErr error
+
+	for _, port := range ports {
+		if port < 1 || port > 65535 {
+			return nil, "", fmt.Errorf("invalid port: %d", port)
+		}
+
+		redirectURI := prefix + strconv.Itoa(port) + suffix
+
+		u, err := url.Parse(redirectURI)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse redirect URI: %v", err)
+		}
+
+		lst, err := net.Listen("tcp", listenAddr(u))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return lst, redirectURI, nil
+	}
+
+	return nil, "", fmt.Errorf("%w: tried ports %v, last error: %v", ErrPortsBusy, formatPorts(ports), lastErr)
+}
+
+// parseRedirectURIPattern splits the pattern around the ports in braces, and
+// returns the ports.
+func parseRedirectURIPattern(pattern string) (string, string, []int, error) {
+	start := strings.Index(pattern, "{")
+	end := strings.Index(pattern, "}")
+
+	if start < 0 || end < start {
+		return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: missing ports in braces", pattern)
+	}
+
+	prefix, suffix := pattern[:start], pattern[end+1:]
+
+	var ports []int
+
+	for _, part := range strings.Split(pattern[start+1:end], ",") {
+		part = strings.TrimSpace(part)
+
+		low, high, isRange := strings.Cut(part, "-")
+		if !isRange {
+			high = low
+		}
+
+		first, err := strconv.Atoi(strings.TrimSpace(low))
+		if err != nil {
+			return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: invalid port %q", pattern, part)
+		}
+
+		last, err := strconv.Atoi(strings.TrimSpace(high))
+		if err != nil {
+			return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: invalid port %q", pattern, part)
+		}
+
+		if first < 1 || last > 65535 || first > last {
+			return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: invalid port %q", pattern, part)
+		}
+
+		if len(ports)+last-first+1 > maxPatternPorts {
+			return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: more than %d ports", pattern, maxPatternPorts)
+		}
+
+		for port := first; port <= last; port++ {
+			ports = append(ports, port)
+		}
+	}
+
+	// Check the rest of the pattern with one of its ports.
+
+	sample := prefix + strconv.Itoa(ports[0]) + suffix
+
+	if err := ims.ValidateRedirectURI(sample, nil); err != nil {
+		return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: %v", pattern, err)
+	}
+
+	u, err := url.Parse(sample)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: %v", pattern, err)
+	}
+
+	if u.Port() != strconv.Itoa(ports[0]) {
+		return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: ports not in the port position", pattern)
+	}
+
+	if !isLoopbackHost(u.Hostname()) {
+		return "", "", nil, fmt.Errorf("invalid redirect URI pattern %q: not a loopback host: %v", pattern, u.Hostname())
+	}
+
+	return prefix, suffix, ports, nil
+}
+
+// listenAddr returns the address to listen on for the redirect URI. For
+// "localhost", 127.0.0.1 is bound explicitly, instead of the first address
+// returned by the resolver, which might be ::1 while the browser uses
+// 127.0.0.1.
+func listenAddr(u *url.URL) string {
+	if strings.EqualFold(u.Hostname(), "localhost") {
+		return net.JoinHostPort("127.0.0.1", u.Port())
+	}
+	return u.Host
+}
+
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+func formatPorts(ports []int) string {
+	s := make([]string, len(ports))
+
+	for i, port := range ports {
+		s[i] = strconv.Itoa(port)
+	}
+
+	return strings.Join(s, ", ")
+}
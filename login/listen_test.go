@@ -0,0 +1,187 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/adobe/ims-go/login"
+)
+
+// busyPort returns a port with a listener on it. The listener is closed at the
+// end of the test.
+func busyPort(t *testing.T) int {
+	t.Helper()
+
+	lst, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	t.Cleanup(func() { _ = lst.Close() })
+
+	return port(lst)
+}
+
+// freePort returns a port that was free a moment ago.
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	lst, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	p := port(lst)
+
+	if err := lst.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	return p
+}
+
+func TestListenPorts(t *testing.T) {
+	busy, free := busyPort(t), freePort(t)
+
+	lst, redirectURI, err := login.Listen(&login.ListenConfig{
+		Ports: []int{busy, free},
+	})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer lst.Close()
+
+	if port(lst) != free {
+		t.Fatalf("invalid port: %v", port(lst))
+	}
+	if want := fmt.Sprintf("http://127.0.0.1:%d/", free); redirectURI != want {
+		t.Fatalf("invalid redirect URI: %v", redirectURI)
+	}
+}
+
+func TestListenRedirectURIPattern(t *testing.T) {
+	busy, free := busyPort(t), freePort(t)
+
+	lst, redirectURI, err := login.Listen(&login.ListenConfig{
+		RedirectURIPattern: fmt.Sprintf("http://127.0.0.1:{%d, %d-%d}/callback", busy, free, free),
+	})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer lst.Close()
+
+	if want := fmt.Sprintf("http://127.0.0.1:%d/callback", free); redirectURI != want {
+		t.Fatalf("invalid redirect URI: %v", redirectURI)
+	}
+}
+
+func TestListenLocalhost(t *testing.T) {
+	free := freePort(t)
+
+	lst, redirectURI, err := login.Listen(&login.ListenConfig{
+		RedirectURIPattern: fmt.Sprintf("http://localhost:{%d}/callback", free),
+	})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer lst.Close()
+
+	if want := fmt.Sprintf("127.0.0.1:%d", free); lst.Addr().String() != want {
+		t.Fatalf("invalid address: %v", lst.Addr())
+	}
+	if want := fmt.Sprintf("http://localhost:%d/callback", free); redirectURI != want {
+		t.Fatalf("invalid redirect URI: %v", redirectURI)
+	}
+}
+
+func TestListenPortsBusy(t *testing.T) {
+	first, second := busyPort(t), busyPort(t)
+
+	_, _, err := login.Listen(&login.ListenConfig{
+		Ports: []int{first, second},
+	})
+
+	if !errors.Is(err, login.ErrPortsBusy) {
+		t.Fatalf("invalid error: %v", err)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("%d, %d", first, second)) {
+		t.Fatalf("ports not reported: %v", err)
+	}
+}
+
+func TestListenInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  login.ListenConfig
+	}{
+		{"empty", login.ListenConfig{}},
+		{"both", login.ListenConfig{Ports: []int{8000}, RedirectURIPattern: "http://127.0.0.1:{8000}/"}},
+		{"invalid port", login.ListenConfig{Ports: []int{70000}}},
+		{"missing braces", login.ListenConfig{RedirectURIPattern: "http://127.0.0.1:8000/"}},
+		{"invalid range", login.ListenConfig{RedirectURIPattern: "http://127.0.0.1:{8010-8000}/"}},
+		{"too many ports", login.ListenConfig{RedirectURIPattern: "http://127.0.0.1:{1-2000}/"}},
+		{"not a port", login.ListenConfig{RedirectURIPattern: "http://127.0.0.1/{8000}"}},
+		{"not loopback", login.ListenConfig{RedirectURIPattern: "https://example.com:{8000}/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := login.Listen(&tt.cfg); err == nil || errors.Is(err, login.ErrPortsBusy) {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunPorts(t *testing.T) {
+	s := runIMSServer(t, "")
+	defer s.Close()
+
+	busy, free := busyPort(t), freePort(t)
+
+	var localURL string
+
+	cfg := newRunConfig(t, s.URL, browser(&localURL))
+	cfg.RedirectURIPattern = fmt.Sprintf("http://localhost:{%d,%d}/callback", busy, free)
+
+	res, err := login.Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if res.AccessToken != "access-token" {
+		t.Fatalf("invalid access token: %v", res.AccessToken)
+	}
+	if !strings.Contains(localURL, fmt.Sprintf(":%d/", free)) {
+		t.Fatalf("invalid local URL: %v", localURL)
+	}
+}
+
+func TestRunPortsBusy(t *testing.T) {
+	cfg := newRunConfig(t, "http://127.0.0.1", func(string) error {
+		t.Fatalf("browser opened")
+		return nil
+	})
+	cfg.Ports = []int{busyPort(t)}
+
+	_, err := login.Run(context.Background(), cfg)
+
+	var runErr *login.RunError
+	if !errors.As(err, &runErr) || runErr.Op != login.RunOpListen || !errors.Is(err, login.ErrPortsBusy) {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
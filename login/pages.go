@@ -0,0 +1,153 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+// profileFetchTimeout bounds the time spent reading the profile of the user
+// before rendering the success page.
+const profileFetchTimeout = 5 * time.Second
+
+// SuccessPage is the data passed to the template of the success page.
+type SuccessPage struct {
+	// ProductName is ServerConfig.ProductName.
+	ProductName string
+	// Email is the email address of the account the user logged in with. It
+	// is empty unless ServerConfig.ShowEmail is set, or if the profile of the
+	// user couldn't be read.
+	Email string
+	// Response is the response of the successful login.
+	Response *ims.TokenResponse
+}
+
+// ErrorPage is the data passed to the template of the error page.
+type ErrorPage struct {
+	// ProductName is ServerConfig.ProductName.
+	ProductName string
+	// Error is the error that made the login fail.
+	Error error
+	// XDebugID is the X-Debug-Id of the failed IMS request, if any. It helps
+	// IMS support troubleshoot the failure.
+	XDebugID string
+	// RetryURL is the URL starting a new login attempt.
+	RetryURL string
+}
+
+const pageStyle = `
+{{define "style"}}
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; background: #f5f5f5; color: #2c2c2c; margin: 0; }
+  main { max-width: 32rem; margin: 12vh auto; padding: 2rem 2.5rem; background: #fff; border-radius: 8px; box-shadow: 0 1px 4px rgba(0, 0, 0, 0.15); }
+  h1 { font-size: 1.5rem; margin-top: 0; }
+  p { line-height: 1.5; }
+  code { background: #f0f0f0; padding: 0.1rem 0.3rem; border-radius: 3px; word-break: break-all; }
+  a.button { display: inline-block; padding: 0.5rem 1.25rem; border-radius: 16px; background: #1473e6; color: #fff; text-decoration: none; }
+  .hint { color: #6e6e6e; font-size: 0.875rem; }
+</style>
+{{end}}
+`
+
+// DefaultSuccessTemplate is the template of the success page used when
+// neither ServerConfig.OnSuccess nor ServerConfig.SuccessTemplate is
+// provided. The page tries to close its window after a few seconds, which
+// browsers only allow for windows opened by a script.
+var DefaultSuccessTemplate = template.Must(template.New("success").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .ProductName}}{{.ProductName}} - {{end}}Login successful</title>
+{{template "style"}}
+</head>
+<body>
+<main>
+  <h1>You are logged in{{if .ProductName}} to {{.ProductName}}{{end}}</h1>
+  {{if .Email}}<p>Signed in as <strong>{{.Email}}</strong>.</p>{{end}}
+  <p>You can close this window and return to the application.</p>
+</main>
+<script>setTimeout(function () { window.close(); }, 3000);</script>
+</body>
+</html>
+` + pageStyle))
+
+// DefaultErrorTemplate is the template of the error page used when neither
+// ServerConfig.OnError nor ServerConfig.ErrorTemplate is provided.
+var DefaultErrorTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .ProductName}}{{.ProductName}} - {{end}}Login failed</title>
+{{template "style"}}
+</head>
+<body>
+<main>
+  <h1>The login{{if .ProductName}} to {{.ProductName}}{{end}} failed</h1>
+  <p>{{.Error}}</p>
+  <p><a class="button" href="{{.RetryURL}}">Try again</a></p>
+  {{if .XDebugID}}<p class="hint">If the problem persists, contact support and mention the debug ID <code>{{.XDebugID}}</code>.</p>{{end}}
+</main>
+</body>
+</html>
+` + pageStyle))
+
+type profileBackend interface {
+	GetProfileWithContext(ctx context.Context, r *ims.GetProfileRequest) (*ims.GetProfileResponse, error)
+}
+
+// profileEmail returns the email address in the profile of the user, or an
+// empty string if the profile can't be read.
+func profileEmail(ctx context.Context, client profileBackend, accessToken string) string {
+	if client == nil || accessToken == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, profileFetchTimeout)
+	defer cancel()
+
+	res, err := client.GetProfileWithContext(ctx, &ims.GetProfileRequest{
+		AccessToken: accessToken,
+	})
+	if err != nil {
+		return ""
+	}
+
+	var profile struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.Unmarshal(res.Body, &profile); err != nil {
+		return ""
+	}
+
+	return profile.Email
+}
+
+func renderPage(w http.ResponseWriter, t *template.Template, data interface{}) {
+	var buf bytes.Buffer
+
+	if err := t.Execute(&buf, data); err != nil {
+		http.Error(w, "Error rendering page.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	_, _ = buf.WriteTo(w)
+}
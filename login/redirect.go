@@ -11,8 +11,10 @@
 package login
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -21,31 +23,120 @@ type redirectBackend interface {
 	AuthorizeURL(cfg *ims.AuthorizeURLConfig) (string, error)
 }
 
+type pushBackend interface {
+	PushAuthorizationWithContext(ctx context.Context, r *ims.PushedAuthorizationRequest) (*ims.PushedAuthorizationResponse, error)
+	PushedAuthorizeURL(clientID, requestURI string) (string, error)
+}
+
 type redirectMiddleware struct {
 	client       redirectBackend
+	push         pushBackend
+	clientSecret string
+	clientAuth   ims.ClientAuthenticator
 	clientID     string
 	scope        []string
-	state        string
+	attempts     *attempts
 	redirectURI  string
 	next         http.Handler
-	codeVerifier string
 	resource     []string
+	prompt       []string
+	loginHint    string
+	locale       string
+	nonce        string
+	maxAge       *time.Duration
+	acrValues    []string
+	idpFlow      string
+	providerID   string
+	extraParams  map[string]string
+}
+
+func newRedirectMiddleware(cfg *ServerConfig, attempts *attempts, next http.Handler) *redirectMiddleware {
+	var push pushBackend
+	if cfg.UsePAR {
+		push = cfg.Client
+	}
+
+	return &redirectMiddleware{
+		client:       cfg.Client,
+		push:         push,
+		clientSecret: cfg.ClientSecret,
+		clientAuth:   cfg.ClientAuth,
+		clientID:     cfg.ClientID,
+		scope:        cfg.Scope,
+		attempts:     attempts,
+		redirectURI:  cfg.RedirectURI,
+		next:         next,
+		resource:     cfg.Resource,
+		prompt:       cfg.Prompt,
+		loginHint:    cfg.LoginHint,
+		locale:       cfg.Locale,
+		nonce:        cfg.Nonce,
+		maxAge:       cfg.MaxAge,
+		acrValues:    cfg.ACRValues,
+		idpFlow:      cfg.IDPFlow,
+		providerID:   cfg.ProviderID,
+		extraParams:  cfg.ExtraParams,
+	}
 }
 
 func (h *redirectMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	url, err := h.client.AuthorizeURL(&ims.AuthorizeURLConfig{
+	_, url, err := h.startAttempt(r.Context())
+	if err != nil {
+		serveError(h.next, w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// startAttempt starts a new login attempt and returns it with its
+// authorization URL.
+func (h *redirectMiddleware) startAttempt(ctx context.Context) (*attempt, string, error) {
+	at, err := h.attempts.start()
+	if err != nil {
+		return nil, "", fmt.Errorf("start login attempt: %v", err)
+	}
+
+	cfg := ims.AuthorizeURLConfig{
 		ClientID:     h.clientID,
 		GrantType:    ims.GrantTypeCode,
 		Scope:        h.scope,
-		State:        h.state,
+		State:        at.state,
 		RedirectURI:  h.redirectURI,
-		CodeVerifier: h.codeVerifier,
+		CodeVerifier: at.codeVerifier,
 		Resource:     h.resource,
+		Prompt:       h.prompt,
+		LoginHint:    h.loginHint,
+		Locale:       h.locale,
+		Nonce:        h.nonce,
+		MaxAge:       h.maxAge,
+		ACRValues:    h.acrValues,
+		IDPFlow:      h.idpFlow,
+		ProviderID:   h.providerID,
+		ExtraParams:  h.extraParams,
+	}
+
+	url, err := h.authorizeURL(ctx, &cfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("generate authorization URL: %v", err)
+	}
+
+	return at, url, nil
+}
+
+func (h *redirectMiddleware) authorizeURL(ctx context.Context, cfg *ims.AuthorizeURLConfig) (string, error) {
+	if h.push == nil {
+		return h.client.AuthorizeURL(cfg)
+	}
+
+	res, err := h.push.PushAuthorizationWithContext(ctx, &ims.PushedAuthorizationRequest{
+		AuthorizeURLConfig: *cfg,
+		ClientSecret:       h.clientSecret,
+		ClientAuth:         h.clientAuth,
 	})
 	if err != nil {
-		serveError(h.next, w, r, fmt.Errorf("generate authorization URL: %v", err))
-		return
+		return "", fmt.Errorf("push authorization request: %v", err)
 	}
 
-	http.Redirect(w, r, url, http.StatusFound)
+	return h.push.PushedAuthorizeURL(cfg.ClientID, res.RequestURI)
 }
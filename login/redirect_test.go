@@ -11,10 +11,12 @@
 package login
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -25,16 +27,28 @@ func (b testRedirectBackend) AuthorizeURL(cfg *ims.AuthorizeURLConfig) (string,
 	return b(cfg)
 }
 
+type testPushBackend struct {
+	push func(r *ims.PushedAuthorizationRequest) (*ims.PushedAuthorizationResponse, error)
+}
+
+func (b *testPushBackend) PushAuthorizationWithContext(_ context.Context, r *ims.PushedAuthorizationRequest) (*ims.PushedAuthorizationResponse, error) {
+	return b.push(r)
+}
+
+func (b *testPushBackend) PushedAuthorizeURL(clientID, requestURI string) (string, error) {
+	return fmt.Sprintf("http://acme.com/login?client_id=%s&request_uri=%s", clientID, requestURI), nil
+}
+
 func TestRedirect(t *testing.T) {
 	m := &redirectMiddleware{
 		clientID: "client-id",
 		scope:    []string{"a", "b"},
-		state:    "state",
+		attempts: newAttempts(false),
 		client: testRedirectBackend(func(cfg *ims.AuthorizeURLConfig) (string, error) {
 			if cfg.ClientID != "client-id" {
 				t.Fatalf("invalid client ID: %v", cfg.ClientID)
 			}
-			if cfg.State != "state" {
+			if cfg.State == "" {
 				t.Fatalf("invalid state: %v", cfg.State)
 			}
 			if len(cfg.Scope) != 2 && cfg.Scope[0] != "a" && cfg.Scope[1] != "b" {
@@ -58,11 +72,132 @@ func TestRedirect(t *testing.T) {
 	}
 }
 
+func TestRedirectExtendedParams(t *testing.T) {
+	maxAge := time.Minute
+
+	m := &redirectMiddleware{
+		clientID:    "client-id",
+		scope:       []string{"a"},
+		attempts:    newAttempts(false),
+		prompt:      []string{ims.PromptLogin},
+		loginHint:   "user@example.com",
+		locale:      "de_DE",
+		nonce:       "nonce",
+		maxAge:      &maxAge,
+		acrValues:   []string{"mfa"},
+		idpFlow:     "social.google",
+		providerID:  "provider",
+		extraParams: map[string]string{"key": "value"},
+		client: testRedirectBackend(func(cfg *ims.AuthorizeURLConfig) (string, error) {
+			if len(cfg.Prompt) != 1 || cfg.Prompt[0] != ims.PromptLogin {
+				t.Fatalf("invalid prompt: %v", cfg.Prompt)
+			}
+			if cfg.LoginHint != "user@example.com" {
+				t.Fatalf("invalid login hint: %v", cfg.LoginHint)
+			}
+			if cfg.Locale != "de_DE" {
+				t.Fatalf("invalid locale: %v", cfg.Locale)
+			}
+			if cfg.Nonce != "nonce" {
+				t.Fatalf("invalid nonce: %v", cfg.Nonce)
+			}
+			if cfg.MaxAge == nil || *cfg.MaxAge != time.Minute {
+				t.Fatalf("invalid max age: %v", cfg.MaxAge)
+			}
+			if len(cfg.ACRValues) != 1 || cfg.ACRValues[0] != "mfa" {
+				t.Fatalf("invalid ACR values: %v", cfg.ACRValues)
+			}
+			if cfg.IDPFlow != "social.google" {
+				t.Fatalf("invalid IDP flow: %v", cfg.IDPFlow)
+			}
+			if cfg.ProviderID != "provider" {
+				t.Fatalf("invalid provider ID: %v", cfg.ProviderID)
+			}
+			if cfg.ExtraParams["key"] != "value" {
+				t.Fatalf("invalid extra params: %v", cfg.ExtraParams)
+			}
+			return "http://acme.com/login", nil
+		}),
+	}
+
+	w := httptest.NewRecorder()
+
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if r := w.Result(); r.StatusCode != http.StatusFound {
+		t.Fatalf("invalid status code: %v", r.StatusCode)
+	}
+}
+
+func TestRedirectPAR(t *testing.T) {
+	m := &redirectMiddleware{
+		clientID:     "client-id",
+		clientSecret: "client-secret",
+		scope:        []string{"a", "b"},
+		attempts:     newAttempts(false),
+		client: testRedirectBackend(func(cfg *ims.AuthorizeURLConfig) (string, error) {
+			t.Fatalf("authorization URL built without PAR")
+			return "", nil
+		}),
+		push: &testPushBackend{
+			push: func(r *ims.PushedAuthorizationRequest) (*ims.PushedAuthorizationResponse, error) {
+				if r.ClientID != "client-id" {
+					t.Fatalf("invalid client ID: %v", r.ClientID)
+				}
+				if r.ClientSecret != "client-secret" {
+					t.Fatalf("invalid client secret: %v", r.ClientSecret)
+				}
+				if r.State == "" {
+					t.Fatalf("invalid state: %v", r.State)
+				}
+				return &ims.PushedAuthorizationResponse{RequestURI: "request-uri"}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	r := w.Result()
+
+	if r.StatusCode != http.StatusFound {
+		t.Fatalf("invalid status code: %v", r.StatusCode)
+	}
+	if h := r.Header.Get("location"); h != "http://acme.com/login?client_id=client-id&request_uri=request-uri" {
+		t.Fatalf("invalid location: %v", h)
+	}
+}
+
+func TestRedirectPARError(t *testing.T) {
+	m := &redirectMiddleware{
+		clientID: "client-id",
+		scope:    []string{"a", "b"},
+		attempts: newAttempts(false),
+		push: &testPushBackend{
+			push: func(r *ims.PushedAuthorizationRequest) (*ims.PushedAuthorizationResponse, error) {
+				return nil, fmt.Errorf("error")
+			},
+		},
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err, ok := r.Context().Value(contextKeyError).(error)
+			if !ok {
+				t.Fatalf("invalid context value")
+			}
+			if err.Error() != "generate authorization URL: push authorization request: error" {
+				t.Fatalf("invalid error: %v", err)
+			}
+		}),
+	}
+
+	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
 func TestRedirectBackendError(t *testing.T) {
 	m := &redirectMiddleware{
 		clientID: "client-id",
 		scope:    []string{"a", "b"},
-		state:    "state",
+		attempts: newAttempts(false),
 		client: testRedirectBackend(func(cfg *ims.AuthorizeURLConfig) (string, error) {
 			return "", fmt.Errorf("error")
 		}),
@@ -82,3 +217,27 @@ func TestRedirectBackendError(t *testing.T) {
 
 	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, "/", nil))
 }
+
+func TestRedirectFreshAttempts(t *testing.T) {
+	var states, verifiers []string
+
+	m := &redirectMiddleware{
+		clientID: "client-id",
+		attempts: newAttempts(true),
+		client: testRedirectBackend(func(cfg *ims.AuthorizeURLConfig) (string, error) {
+			states = append(states, cfg.State)
+			verifiers = append(verifiers, cfg.CodeVerifier)
+			return "http://acme.com/login", nil
+		}),
+	}
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(states) != 2 || states[0] == states[1] {
+		t.Fatalf("state reused: %v", states)
+	}
+	if verifiers[0] == "" || verifiers[0] == verifiers[1] {
+		t.Fatalf("code verifier reused: %v", verifiers)
+	}
+}
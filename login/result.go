@@ -12,54 +12,108 @@ package login
 
 import (
 	"fmt"
+	"html"
+	"html/template"
 	"net/http"
 
 	"github.com/adobe/ims-go/ims"
 )
 
 type resultHandler struct {
-	successHandler http.Handler
-	failureHandler http.Handler
-	resCh          chan<- *ims.TokenResponse
-	errCh          chan<- error
+	successHandler  http.Handler
+	failureHandler  http.Handler
+	successTemplate *template.Template
+	errorTemplate   *template.Template
+	productName     string
+	// profile reads the email of the user for the success page. Optional.
+	profile profileBackend
+	// startPath is linked from the error page to try again.
+	startPath string
+	// deliverResult delivers the response of a successful attempt. It returns
+	// false if another attempt already succeeded.
+	deliverResult func(*ims.TokenResponse) bool
+	// deliverError delivers the error of a failed attempt. It returns false if
+	// another attempt already succeeded.
+	deliverError func(error) bool
 }
 
 func (h *resultHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if result, ok := r.Context().Value(contextKeyResult).(*ims.TokenResponse); ok {
+	if result, ok := ResultFromContext(r.Context()); ok {
+		if !h.deliverResult(result) {
+			serveCompleted(w, r)
+			return
+		}
+
 		if h.successHandler != nil {
 			h.successHandler.ServeHTTP(w, r)
 		} else {
-			_, _ = fmt.Fprintf(w, "Success!")
-		}
-
-		select {
-		case h.resCh <- result:
-			// Result sent.
-		case <-r.Context().Done():
-			// Request cancelled.
+			h.serveSuccessPage(w, r, result)
 		}
 
 		return
 	}
 
-	var serverErr error
-
-	if err, ok := r.Context().Value(contextKeyError).(error); ok {
-		serverErr = err
-	} else {
+	serverErr := ErrorFromContext(r.Context())
+	if serverErr == nil {
 		serverErr = fmt.Errorf("neither error nor result returned")
 	}
 
+	if !h.deliverError(serverErr) {
+		serveCompleted(w, r)
+		return
+	}
+
 	if h.failureHandler != nil {
 		h.failureHandler.ServeHTTP(w, r)
 	} else {
-		_, _ = fmt.Fprintf(w, "Error: %v", serverErr)
+		h.serveErrorPage(w, serverErr)
+	}
+}
+
+func (h *resultHandler) serveSuccessPage(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) {
+	t := h.successTemplate
+	if t == nil {
+		t = DefaultSuccessTemplate
+	}
+
+	renderPage(w, t, &SuccessPage{
+		ProductName: h.productName,
+		Email:       profileEmail(r.Context(), h.profile, res.AccessToken),
+		Response:    res,
+	})
+}
+
+func (h *resultHandler) serveErrorPage(w http.ResponseWriter, err error) {
+	t := h.errorTemplate
+	if t == nil {
+		t = DefaultErrorTemplate
 	}
 
-	select {
-	case h.errCh <- serverErr:
-		// Error sent.
-	case <-r.Context().Done():
-		// Request cancelled.
+	var xDebugID string
+
+	if imsErr, ok := ims.IsError(err); ok {
+		xDebugID = imsErr.XDebugID
 	}
+
+	renderPage(w, t, &ErrorPage{
+		ProductName: h.productName,
+		Error:       err,
+		XDebugID:    xDebugID,
+		RetryURL:    normalizePath(h.startPath),
+	})
+}
+
+// serveCompleted serves the requests received after a login attempt
+// succeeded.
+func serveCompleted(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprintf(w, "Login already completed. You can close this window.")
+}
+
+// retryHandler serves the callbacks of login attempts already done, e.g. when
+// the user reloads the error page.
+func retryHandler(startPath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = fmt.Fprintf(w, "<p>This login attempt is over.</p><p><a href=\"%s\">Try again</a></p>", html.EscapeString(startPath))
+	})
 }
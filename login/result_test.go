@@ -13,125 +13,259 @@ package login
 import (
 	"context"
 	"fmt"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/adobe/ims-go/ims"
 )
 
-func TestResult(t *testing.T) {
-	resCh := make(chan *ims.TokenResponse)
+// testDelivery records the outcomes delivered by a resultHandler.
+type testDelivery struct {
+	results   []*ims.TokenResponse
+	errs      []error
+	completed bool
+}
 
-	h := &resultHandler{
-		resCh: resCh,
+func (d *testDelivery) deliverResult(res *ims.TokenResponse) bool {
+	if d.completed {
+		return false
 	}
+	d.completed = true
+	d.results = append(d.results, res)
+	return true
+}
 
-	w := httptest.NewRecorder()
+func (d *testDelivery) deliverError(err error) bool {
+	if d.completed {
+		return false
+	}
+	d.errs = append(d.errs, err)
+	return true
+}
+
+func newTestResultHandler(d *testDelivery) *resultHandler {
+	return &resultHandler{
+		startPath:     "/",
+		deliverResult: d.deliverResult,
+		deliverError:  d.deliverError,
+	}
+}
+
+func resultRequest() *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	return r.WithContext(context.WithValue(r.Context(), contextKeyResult, &ims.TokenResponse{}))
+}
 
+func errorRequest(err error) *http.Request {
 	r := httptest.NewRequest(http.MethodGet, "/", nil)
-	r = r.WithContext(context.WithValue(r.Context(), contextKeyResult, &ims.TokenResponse{}))
+	return r.WithContext(context.WithValue(r.Context(), contextKeyError, err))
+}
+
+func TestResult(t *testing.T) {
+	var d testDelivery
 
-	go h.ServeHTTP(w, r)
+	w := httptest.NewRecorder()
+
+	newTestResultHandler(&d).ServeHTTP(w, resultRequest())
 
-	if res := <-resCh; res == nil {
+	if len(d.results) != 1 || d.results[0] == nil {
 		t.Fatalf("expected a response")
 	}
 
-	if s := w.Body.String(); s != "Success!" {
+	if s := w.Body.String(); !strings.Contains(s, "You are logged in") || !strings.Contains(s, "window.close()") {
 		t.Fatalf("invalid body: %v", s)
 	}
 }
 
 func TestResultError(t *testing.T) {
-	errCh := make(chan error)
-
-	h := &resultHandler{
-		errCh: errCh,
-	}
+	var d testDelivery
 
 	w := httptest.NewRecorder()
 
-	r := httptest.NewRequest(http.MethodGet, "/", nil)
-	r = r.WithContext(context.WithValue(r.Context(), contextKeyError, fmt.Errorf("error")))
-
-	go h.ServeHTTP(w, r)
+	newTestResultHandler(&d).ServeHTTP(w, errorRequest(fmt.Errorf("<error>")))
 
-	if err := <-errCh; err == nil {
-		t.Fatalf("error expected")
-	} else if err.Error() != "error" {
-		t.Fatalf("invalid error: %v", err)
+	if len(d.errs) != 1 || d.errs[0].Error() != "<error>" {
+		t.Fatalf("invalid errors: %v", d.errs)
 	}
 
-	if s := w.Body.String(); s != "Error: error" {
+	if s := w.Body.String(); !strings.Contains(s, "<p>&lt;error&gt;</p>") || !strings.Contains(s, `href="/"`) {
 		t.Fatalf("invalid body: %v", s)
 	}
 }
 
 func TestResultSuccessHandler(t *testing.T) {
-	resCh := make(chan *ims.TokenResponse)
+	var d testDelivery
 
-	h := &resultHandler{
-		resCh: resCh,
-		successHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			_, _ = fmt.Fprint(w, "custom")
-		}),
-	}
+	h := newTestResultHandler(&d)
+	h.successHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "custom")
+	})
 
 	w := httptest.NewRecorder()
 
-	r := httptest.NewRequest(http.MethodGet, "/", nil)
-	r = r.WithContext(context.WithValue(r.Context(), contextKeyResult, &ims.TokenResponse{}))
+	h.ServeHTTP(w, resultRequest())
+
+	if s := w.Body.String(); s != "custom" {
+		t.Fatalf("invalid body: %v", s)
+	}
+}
+
+func TestResultFailureHandler(t *testing.T) {
+	var d testDelivery
+
+	h := newTestResultHandler(&d)
+	h.failureHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "custom")
+	})
 
-	go h.ServeHTTP(w, r)
+	w := httptest.NewRecorder()
 
-	<-resCh
+	h.ServeHTTP(w, errorRequest(fmt.Errorf("error")))
 
 	if s := w.Body.String(); s != "custom" {
 		t.Fatalf("invalid body: %v", s)
 	}
 }
 
-func TestResultFailureHandler(t *testing.T) {
-	errCh := make(chan error)
+func TestResultInvalidContext(t *testing.T) {
+	var d testDelivery
 
-	h := &resultHandler{
-		errCh: errCh,
-		failureHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			_, _ = fmt.Fprint(w, "custom")
-		}),
+	newTestResultHandler(&d).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(d.errs) != 1 {
+		t.Fatalf("error expected")
+	} else if d.errs[0].Error() != "neither error nor result returned" {
+		t.Fatalf("invalid error: %v", d.errs[0])
 	}
+}
 
-	w := httptest.NewRecorder()
+func TestResultSingleOutcome(t *testing.T) {
+	var d testDelivery
+
+	h := newTestResultHandler(&d)
+
+	h.ServeHTTP(httptest.NewRecorder(), resultRequest())
+
+	// Outcomes received after a success are not delivered.
+
+	for _, r := range []*http.Request{resultRequest(), errorRequest(fmt.Errorf("error"))} {
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+
+		if s := w.Body.String(); s != "Login already completed. You can close this window." {
+			t.Fatalf("invalid body: %v", s)
+		}
+	}
+
+	if len(d.results) != 1 || len(d.errs) != 0 {
+		t.Fatalf("invalid outcomes: %v, %v", d.results, d.errs)
+	}
+}
+
+type testProfileBackend func(r *ims.GetProfileRequest) (*ims.GetProfileResponse, error)
+
+func (b testProfileBackend) GetProfileWithContext(_ context.Context, r *ims.GetProfileRequest) (*ims.GetProfileResponse, error) {
+	return b(r)
+}
+
+func TestResultSuccessPage(t *testing.T) {
+	var d testDelivery
+
+	h := newTestResultHandler(&d)
+	h.productName = "Acme CLI"
+	h.profile = testProfileBackend(func(r *ims.GetProfileRequest) (*ims.GetProfileResponse, error) {
+		if r.AccessToken != "access-token" {
+			t.Fatalf("invalid access token: %v", r.AccessToken)
+		}
+		return &ims.GetProfileResponse{
+			Response: ims.Response{Body: []byte(`{"email":"user@example.com"}`)},
+		}, nil
+	})
 
 	r := httptest.NewRequest(http.MethodGet, "/", nil)
-	r = r.WithContext(context.WithValue(r.Context(), contextKeyError, fmt.Errorf("error")))
+	r = r.WithContext(context.WithValue(r.Context(), contextKeyResult, &ims.TokenResponse{AccessToken: "access-token"}))
+
+	w := httptest.NewRecorder()
 
-	go h.ServeHTTP(w, r)
+	h.ServeHTTP(w, r)
 
-	<-errCh
+	s := w.Body.String()
 
-	if s := w.Body.String(); s != "custom" {
+	if !strings.Contains(s, "Acme CLI") || !strings.Contains(s, "user@example.com") {
 		t.Fatalf("invalid body: %v", s)
 	}
+	if v := w.Header().Get("Content-Type"); v != "text/html; charset=utf-8" {
+		t.Fatalf("invalid content type: %v", v)
+	}
 }
 
-func TestResultInvalidContext(t *testing.T) {
-	errCh := make(chan error)
+func TestResultErrorPageDebugID(t *testing.T) {
+	var d testDelivery
+
+	h := newTestResultHandler(&d)
+	h.startPath = "/login"
 
-	h := &resultHandler{
-		errCh: errCh,
+	err := fmt.Errorf("obtaining access token: %w", &ims.Error{
+		Response: ims.Response{StatusCode: http.StatusBadRequest, XDebugID: "debug-id"},
+	})
+
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, errorRequest(err))
+
+	if s := w.Body.String(); !strings.Contains(s, "<code>debug-id</code>") || !strings.Contains(s, `href="/login"`) {
+		t.Fatalf("invalid body: %v", s)
 	}
+}
+
+func TestResultCustomTemplates(t *testing.T) {
+	var d testDelivery
+
+	h := newTestResultHandler(&d)
+	h.productName = "Acme"
+	h.successTemplate = template.Must(template.New("success").Parse(`{{.ProductName}}: {{.Response.AccessToken}}`))
+	h.errorTemplate = template.Must(template.New("error").Parse(`{{.ProductName}}: {{.Error}}`))
 
 	w := httptest.NewRecorder()
 
+	h.ServeHTTP(w, errorRequest(fmt.Errorf("<denied>")))
+
+	if s := w.Body.String(); s != "Acme: &lt;denied&gt;" {
+		t.Fatalf("invalid error body: %v", s)
+	}
+
 	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), contextKeyResult, &ims.TokenResponse{AccessToken: "token"}))
 
-	go h.ServeHTTP(w, r)
+	w = httptest.NewRecorder()
 
-	if err := <-errCh; err == nil {
-		t.Fatalf("error expected")
-	} else if err.Error() != "neither error nor result returned" {
-		t.Fatalf("invalid error: %v", err)
+	h.ServeHTTP(w, r)
+
+	if s := w.Body.String(); s != "Acme: token" {
+		t.Fatalf("invalid success body: %v", s)
+	}
+}
+
+func TestContextAccessors(t *testing.T) {
+	var (
+		res = &ims.TokenResponse{}
+		err = fmt.Errorf("error")
+	)
+
+	if got, ok := ResultFromContext(resultRequest().Context()); !ok || got == nil {
+		t.Fatalf("result not found")
+	}
+	if got := ErrorFromContext(errorRequest(err).Context()); got != err {
+		t.Fatalf("invalid error: %v", got)
+	}
+	if _, ok := ResultFromContext(errorRequest(err).Context()); ok {
+		t.Fatalf("unexpected result")
+	}
+	if got := ErrorFromContext(context.WithValue(context.Background(), contextKeyResult, res)); got != nil {
+		t.Fatalf("unexpected error: %v", got)
 	}
 }
@@ -15,17 +15,47 @@ import (
 )
 
 type routeMiddleware struct {
-	redirect http.Handler
-	callback http.Handler
+	startPath    string
+	callbackPath string
+	redirect     http.Handler
+	callback     http.Handler
+	// completed reports whether the outcome of the login was delivered.
+	completed func() bool
+	// completedHandler serves the requests received after the outcome of the
+	// login was delivered.
+	completedHandler http.Handler
 }
 
 func (h *routeMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
+	var (
+		path         = normalizePath(r.URL.Path)
+		startPath    = normalizePath(h.startPath)
+		callbackPath = normalizePath(h.callbackPath)
+		q            = r.URL.Query()
+		isCallback   = path == callbackPath && (q.Get("code") != "" || q.Get("error") != "")
+	)
 
-	if q.Get("code") != "" || q.Get("error") != "" {
+	if !isCallback && path != startPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	if h.completed != nil && h.completed() {
+		h.completedHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if isCallback {
 		h.callback.ServeHTTP(w, r)
 		return
 	}
 
 	h.redirect.ServeHTTP(w, r)
 }
+
+func normalizePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
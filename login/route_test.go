@@ -71,3 +71,68 @@ func TestRouteWithError(t *testing.T) {
 		t.Fatalf("callback handler not invoked")
 	}
 }
+
+func TestRouteNotFound(t *testing.T) {
+	m := &routeMiddleware{
+		startPath:    "/",
+		callbackPath: "/callback",
+		redirect: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatalf("redirect handler invoked")
+		}),
+		callback: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatalf("callback handler invoked")
+		}),
+	}
+
+	for _, target := range []string{"/favicon.ico", "/callback", "/other?code=code"} {
+		w := httptest.NewRecorder()
+
+		m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("invalid status code for %v: %v", target, w.Code)
+		}
+	}
+}
+
+func TestRouteCallbackPath(t *testing.T) {
+	var callback bool
+
+	m := &routeMiddleware{
+		startPath:    "/login",
+		callbackPath: "/callback",
+		callback: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			callback = true
+		}),
+	}
+
+	target := urlWithParams("/callback", map[string]string{
+		"code": "code",
+	})
+
+	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if !callback {
+		t.Fatalf("callback handler not invoked")
+	}
+}
+
+func TestRouteCompleted(t *testing.T) {
+	var completed bool
+
+	m := &routeMiddleware{
+		completed: func() bool { return true },
+		completedHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			completed = true
+		}),
+		redirect: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatalf("redirect handler invoked")
+		}),
+	}
+
+	m.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !completed {
+		t.Fatalf("completed handler not invoked")
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+const (
+	defaultRunAddr            = "127.0.0.1:0"
+	defaultRunTimeout         = 5 * time.Minute
+	defaultRunShutdownTimeout = 5 * time.Second
+)
+
+// ErrTimeout is the error wrapped by RunError when the user doesn't complete
+// the login within RunConfig.Timeout.
+var ErrTimeout = errors.New("login timed out")
+
+// RunOp is the step of Run that failed.
+type RunOp string
+
+const (
+	// RunOpListen means that the local server couldn't listen.
+	RunOpListen RunOp = "listen"
+	// RunOpCreateServer means that the configuration of the server is invalid.
+	RunOpCreateServer RunOp = "create server"
+	// RunOpServe means that the local server stopped unexpectedly.
+	RunOpServe RunOp = "serve"
+	// RunOpOpenBrowser means that the browser couldn't be opened.
+	RunOpOpenBrowser RunOp = "open browser"
+	// RunOpReadInput means that the input of the user couldn't be read or
+	// parsed in headless mode.
+	RunOpReadInput RunOp = "read input"
+	// RunOpLogin means that the login failed, e.g. because the user denied
+	// the consent or the authorization code couldn't be exchanged, and that
+	// the user didn't try again before the timeout. Err is the error of the
+	// last attempt.
+	RunOpLogin RunOp = "login"
+	// RunOpWait means that the login wasn't completed in time, or that the
+	// context was cancelled. Err is either ErrTimeout or the error of the
+	// context.
+	RunOpWait RunOp = "wait"
+)
+
+// RunError is the error returned by Run.
+type RunError struct {
+	// Op is the step that failed.
+	Op RunOp
+	// Err is the cause of the failure.
+	Err error
+}
+
+func (e *RunError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Op, e.Err)
+}
+
+func (e *RunError) Unwrap() error {
+	return e.Err
+}
+
+// RunConfig is the configuration for Run.
+type RunConfig struct {
+	// ServerConfig is the configuration of the login server. If RedirectURI
+	// is empty, it is derived from the address of the listener.
+	ServerConfig
+	// ListenConfig lists the ports allowed by the redirect URIs registered
+	// in IMS. If provided, the server listens on the first free one, the
+	// redirect URI is derived from it, and both Addr and RedirectURI must be
+	// empty.
+	ListenConfig
+	// Addr is the local address to listen to when ListenConfig is empty. If
+	// not provided, it is derived from RedirectURI when its host is a
+	// loopback address with a fixed port, and defaults to "127.0.0.1:0",
+	// which picks a random free port, otherwise. Since IMS only accepts
+	// registered redirect URIs, this is mostly useful with a fixed port. If
+	// both Addr and a loopback RedirectURI are provided, their ports must
+	// match.
+	Addr string
+	// OpenBrowser opens the URL starting the login in the browser of the
+	// user. If not provided, it defaults to OpenBrowser.
+	OpenBrowser func(url string) error
+	// Timeout is how long to wait for the user to complete the login. If not
+	// provided, it defaults to five minutes.
+	Timeout time.Duration
+	// Headless performs the login out of band, for when the browser of the
+	// user can't reach a local server, e.g. over SSH or in a container. The
+	// authorization URL is printed to Output, and the user pastes back on
+	// Input the URL the browser was redirected to, or the code in it.
+	// OpenBrowser is ignored, PKCE is always used, and neither ListenConfig
+	// nor Addr can be provided, since no local server is started.
+	Headless bool
+	// Input is where the user pastes the redirected URL in headless mode.
+	// If not provided, it defaults to os.Stdin.
+	Input io.Reader
+	// Output is where the instructions are printed in headless mode. If not
+	// provided, it defaults to os.Stderr.
+	Output io.Writer
+}
+
+// Run performs a user login in a single call. It starts a login server on a
+// local address, opens the browser of the user, waits for a login attempt to
+// succeed, and shuts the server down. Errors are of type *RunError.
+func Run(ctx context.Context, cfg *RunConfig) (*ims.TokenResponse, error) {
+	if cfg.Timeout < 0 {
+		return nil, &RunError{Op: RunOpCreateServer, Err: fmt.Errorf("invalid timeout: %v", cfg.Timeout)}
+	}
+
+	openBrowser := cfg.OpenBrowser
+	if openBrowser == nil {
+		openBrowser = OpenBrowser
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultRunTimeout
+	}
+
+	if cfg.Headless {
+		if len(cfg.Ports) > 0 || cfg.RedirectURIPattern != "" || cfg.Addr != "" {
+			return nil, &RunError{Op: RunOpCreateServer, Err: fmt.Errorf("headless mode is mutually exclusive with allowed ports and address")}
+		}
+
+		return runHeadless(ctx, cfg, timeout)
+	}
+
+	lst, redirectURI, err := listen(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	startPath := cfg.StartPath
+	if startPath == "" {
+		startPath = "/"
+	}
+
+	localURL := fmt.Sprintf("http://%v%v", lst.Addr(), startPath)
+
+	serverCfg := cfg.ServerConfig
+	if serverCfg.RedirectURI == "" {
+		serverCfg.RedirectURI = redirectURI
+	}
+
+	srv, err := NewServer(&serverCfg)
+	if err != nil {
+		lst.Close()
+		return nil, &RunError{Op: RunOpCreateServer, Err: err}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	serveErrCh := make(chan error, 1)
+
+	go func() {
+		if err := srv.Serve(lst); err != http.ErrServerClosed {
+			serveErrCh <- err
+			cancel()
+		}
+	}()
+
+	defer shutdown(srv)
+
+	if err := openBrowser(localURL); err != nil {
+		return nil, &RunError{Op: RunOpOpenBrowser, Err: err}
+	}
+
+	res, err := srv.Wait(waitCtx)
+	if err == nil {
+		return res, nil
+	}
+
+	select {
+	case err := <-serveErrCh:
+		return nil, &RunError{Op: RunOpServe, Err: err}
+	default:
+	}
+
+	var waitErr *WaitError
+
+	if errors.As(err, &waitErr) && waitErr.LastAttemptErr != nil {
+		return nil, &RunError{Op: RunOpLogin, Err: waitErr.LastAttemptErr}
+	}
+
+	if ctx.Err() != nil {
+		return nil, &RunError{Op: RunOpWait, Err: ctx.Err()}
+	}
+
+	return nil, &RunError{Op: RunOpWait, Err: ErrTimeout}
+}
+
+// listen returns the listener of the server and its default redirect URI.
+func listen(cfg *RunConfig) (net.Listener, string, error) {
+	if len(cfg.Ports) == 0 && cfg.RedirectURIPattern == "" {
+		redirectAddr, port, err := redirectURIAddr(cfg.RedirectURI)
+		if err != nil {
+			return nil, "", &RunError{Op: RunOpCreateServer, Err: err}
+		}
+
+		addr := cfg.Addr
+		if addr == "" {
+			addr = redirectAddr
+		}
+		if addr == "" {
+			addr = defaultRunAddr
+		}
+
+		if _, addrPort, err := net.SplitHostPort(addr); err == nil && port != "" && addrPort != port {
+			return nil, "", &RunError{Op: RunOpCreateServer, Err: fmt.Errorf("redirect URI port %v doesn't match address %v", port, addr)}
+		}
+
+		lst, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, "", &RunError{Op: RunOpListen, Err: err}
+		}
+
+		return lst, fmt.Sprintf("http://%v/", lst.Addr()), nil
+	}
+
+	if cfg.Addr != "" || cfg.RedirectURI != "" {
+		return nil, "", &RunError{Op: RunOpCreateServer, Err: fmt.Errorf("allowed ports are mutually exclusive with address and redirect URI")}
+	}
+
+	lst, redirectURI, err := Listen(&cfg.ListenConfig)
+	if err != nil {
+		return nil, "", &RunError{Op: RunOpListen, Err: err}
+	}
+
+	return lst, redirectURI, nil
+}
+
+// redirectURIAddr returns the address to listen on for the redirect URI and
+// its port, if its host is a loopback address and its port is fixed, and
+// empty strings otherwise.
+func redirectURIAddr(redirectURI string) (string, string, error) {
+	if redirectURI == "" {
+		return "", "", nil
+	}
+
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid redirect URI: %v", err)
+	}
+
+	if !isLoopbackHost(u.Hostname()) || u.Port() == "" || u.Port() == "0" {
+		return "", "", nil
+	}
+
+	return listenAddr(u), u.Port(), nil
+}
+
+func shutdown(srv *Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRunShutdownTimeout)
+	defer cancel()
+
+	_ = srv.Shutdown(ctx)
+}
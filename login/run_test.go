@@ -0,0 +1,213 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/adobe/ims-go/login"
+)
+
+// runIMSServer returns a fake IMS redirecting the browser back to the
+// redirect URI, with the given error if not empty.
+func runIMSServer(t *testing.T, authErr string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/ims/authorize/v1", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		v := url.Values{}
+		v.Set("state", q.Get("state"))
+
+		if authErr != "" {
+			v.Set("error", authErr)
+		} else {
+			v.Set("code", "code")
+		}
+
+		http.Redirect(w, r, q.Get("redirect_uri")+"?"+v.Encode(), http.StatusFound)
+	})
+
+	mux.HandleFunc("/ims/token/v2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"access_token": "access-token", "expires_in": 3600}`)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newRunConfig(t *testing.T, imsURL string, openBrowser func(string) error) *login.RunConfig {
+	t.Helper()
+
+	client, err := ims.NewClient(&ims.ClientConfig{URL: imsURL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	return &login.RunConfig{
+		ServerConfig: login.ServerConfig{
+			Client:       client,
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			Scope:        []string{"openid"},
+		},
+		OpenBrowser: openBrowser,
+	}
+}
+
+// browser simulates a browser following the redirects from the local URL. It
+// stores the local URL in localURL.
+func browser(localURL *string) func(string) error {
+	return func(u string) error {
+		*localURL = u
+
+		go func() {
+			res, err := http.Get(u)
+			if err == nil {
+				res.Body.Close()
+			}
+		}()
+
+		return nil
+	}
+}
+
+func TestRun(t *testing.T) {
+	s := runIMSServer(t, "")
+	defer s.Close()
+
+	var localURL string
+
+	res, err := login.Run(context.Background(), newRunConfig(t, s.URL, browser(&localURL)))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if res.AccessToken != "access-token" {
+		t.Fatalf("invalid access token: %v", res.AccessToken)
+	}
+
+	// The server is shut down.
+
+	if _, err := http.Get(localURL); err == nil {
+		t.Fatalf("server still running")
+	}
+}
+
+func TestRunLoginError(t *testing.T) {
+	s := runIMSServer(t, "access_denied")
+	defer s.Close()
+
+	var localURL string
+
+	// The user doesn't try again after the failed attempt.
+
+	cfg := newRunConfig(t, s.URL, browser(&localURL))
+	cfg.Timeout = 500 * time.Millisecond
+
+	_, err := login.Run(context.Background(), cfg)
+
+	var runErr *login.RunError
+	if !errors.As(err, &runErr) || runErr.Op != login.RunOpLogin || runErr.Err.Error() != "backend error: access_denied" {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	cfg := newRunConfig(t, "http://127.0.0.1", func(string) error { return nil })
+	cfg.Timeout = 10 * time.Millisecond
+
+	_, err := login.Run(context.Background(), cfg)
+
+	var runErr *login.RunError
+	if !errors.As(err, &runErr) || runErr.Op != login.RunOpWait || !errors.Is(err, login.ErrTimeout) {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestRunContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg := newRunConfig(t, "http://127.0.0.1", func(string) error {
+		cancel()
+		return nil
+	})
+
+	_, err := login.Run(ctx, cfg)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestRunOpenBrowserError(t *testing.T) {
+	cfg := newRunConfig(t, "http://127.0.0.1", func(string) error {
+		return fmt.Errorf("no browser")
+	})
+
+	_, err := login.Run(context.Background(), cfg)
+
+	var runErr *login.RunError
+	if !errors.As(err, &runErr) || runErr.Op != login.RunOpOpenBrowser {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestRunRedirectURIAddr(t *testing.T) {
+	s := runIMSServer(t, "")
+	defer s.Close()
+
+	port := freePort(t)
+
+	var localURL string
+
+	// The server listens on the port of the redirect URI.
+
+	cfg := newRunConfig(t, s.URL, browser(&localURL))
+	cfg.RedirectURI = fmt.Sprintf("http://localhost:%d/", port)
+
+	res, err := login.Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if res.AccessToken != "access-token" {
+		t.Fatalf("invalid access token: %v", res.AccessToken)
+	}
+	if localURL != fmt.Sprintf("http://127.0.0.1:%d/", port) {
+		t.Fatalf("invalid local URL: %v", localURL)
+	}
+}
+
+func TestRunRedirectURIAddrMismatch(t *testing.T) {
+	cfg := newRunConfig(t, "http://127.0.0.1", func(string) error {
+		t.Fatalf("browser opened")
+		return nil
+	})
+	cfg.RedirectURI = fmt.Sprintf("http://localhost:%d/", freePort(t))
+	cfg.Addr = "127.0.0.1:0"
+
+	_, err := login.Run(context.Background(), cfg)
+
+	var runErr *login.RunError
+	if !errors.As(err, &runErr) || runErr.Op != login.RunOpCreateServer {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
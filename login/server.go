@@ -15,11 +15,14 @@ package login
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base64"
 	"fmt"
+	"html/template"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -47,10 +50,30 @@ import (
 //
 //	// Close the server.
 //	srv.Shutdown()
+//
+// Every visit of the start page begins a new login attempt, with its own state
+// and code verifier. A user whose attempt failed can try again from the error
+// page. Wait returns only once an attempt succeeded, unlike the select above,
+// which gives up at the first error:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+//	defer cancel()
+//
+//	res, err := srv.Wait(ctx)
+//
+// Run implements this pattern for the common case of a login from a desktop
+// application.
 type Server struct {
-	server *http.Server
-	resCh  chan *ims.TokenResponse
-	errCh  chan error
+	server     *http.Server
+	resCh      chan *ims.TokenResponse
+	errCh      chan error
+	doneCh     chan struct{}
+	shutdownCh chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	res     *ims.TokenResponse
+	lastErr error
 }
 
 // ServerConfig is the configuration for the login server.
@@ -61,83 +84,150 @@ type ServerConfig struct {
 	ClientID string
 	// The client secret.
 	ClientSecret string
+	// The method used to authenticate the client to the token endpoint. If
+	// provided, ClientSecret must be empty.
+	ClientAuth ims.ClientAuthenticator
 	// List of scopes to request.
 	Scope []string
-	// The URL to be redirected after authentication
+	// The URL to be redirected after authentication. If provided, it must be
+	// a valid redirect URI according to ims.ValidateRedirectURI. When using
+	// Listen, pass the redirect URI it returns.
 	RedirectURI string
-	// A custom handler for sending an error response to the client. If not
-	// provided, a default response is sent.
+	// A custom handler for sending an error response to the client. The
+	// error is available through ErrorFromContext. If not provided, the
+	// error page is rendered from ErrorTemplate.
 	OnError http.Handler
-	// A custom handler for sending a success response to the client. If not
-	// provided, a default response is sent.
+	// A custom handler for sending a success response to the client. The
+	// response is available through ResultFromContext. If not provided, the
+	// success page is rendered from SuccessTemplate.
 	OnSuccess http.Handler
+	// SuccessTemplate renders the success page with a *SuccessPage. If not
+	// provided, DefaultSuccessTemplate is used.
+	SuccessTemplate *template.Template
+	// ErrorTemplate renders the error page with an *ErrorPage. If not
+	// provided, DefaultErrorTemplate is used.
+	ErrorTemplate *template.Template
+	// ProductName is the name of the application shown on the success and
+	// error pages. Optional.
+	ProductName string
+	// ShowEmail reads the profile of the user to show their email address
+	// on the success page. The page is rendered after the profile is read,
+	// which can take up to five seconds.
+	ShowEmail bool
 	// Use PKCE in the authorization code flow.
 	UsePKCE bool
 	// Resource is the RFC 8707 resource indicator(s) for audience-restricted
 	// tokens. Sent on the authorize request to bind the resource to the
 	// authorization code. Optional.
 	Resource []string
+	// Prompt is the list of prompts to force on the user, e.g.
+	// ims.PromptSelectAccount. Optional.
+	Prompt []string
+	// LoginHint is a hint about the login identifier the user might use.
+	// Optional.
+	LoginHint string
+	// Locale is the preferred locale for the login pages. Optional.
+	Locale string
+	// Nonce is a value bound to the ID token. Optional.
+	Nonce string
+	// MaxAge is the maximum time elapsed since the last active
+	// authentication of the user. Optional.
+	MaxAge *time.Duration
+	// ACRValues is the list of requested authentication context class
+	// references. Optional.
+	ACRValues []string
+	// IDPFlow is a hint about the identity provider flow to use. Optional.
+	IDPFlow string
+	// ProviderID is a hint about the identity provider to use. Optional.
+	ProviderID string
+	// ExtraParams are additional parameters added to the authorization URL.
+	// Optional.
+	ExtraParams map[string]string
+	// Use a pushed authorization request (RFC 9126). The authorization
+	// parameters are sent directly to IMS, and the browser is redirected to
+	// a compact authorization URL referencing them.
+	UsePAR bool
+	// StartPath is the path of the URL starting the login. If not provided,
+	// it defaults to "/".
+	StartPath string
+	// CallbackPath is the path receiving the redirect from IMS. If not
+	// provided, it defaults to the path of RedirectURI, or to "/" if
+	// RedirectURI is empty. Requests to paths other than StartPath and
+	// CallbackPath are answered with 404.
+	CallbackPath string
 }
 
 // NewServer creates a new Server for the provided ServerConfig.
 func NewServer(cfg *ServerConfig) (*Server, error) {
-	state, err := randomState()
-	if err != nil {
-		return nil, fmt.Errorf("generate random state: %v", err)
+	if cfg.RedirectURI != "" {
+		if err := ims.ValidateRedirectURI(cfg.RedirectURI, nil); err != nil {
+			return nil, err
+		}
 	}
 
-	codeVerifier := ""
-	if cfg.UsePKCE {
-		codeVerifier, err = randomCodeVerifier()
+	startPath := cfg.StartPath
+	if startPath == "" {
+		startPath = "/"
+	}
+
+	callbackPath := cfg.CallbackPath
+	if callbackPath == "" && cfg.RedirectURI != "" {
+		u, err := url.Parse(cfg.RedirectURI)
 		if err != nil {
-			return nil, fmt.Errorf("generate random code verifier: %v", err)
+			return nil, fmt.Errorf("parse redirect URI: %v", err)
 		}
+		callbackPath = u.Path
+	}
+	if callbackPath == "" {
+		callbackPath = "/"
+	}
+
+	if !strings.HasPrefix(startPath, "/") {
+		return nil, fmt.Errorf("invalid start path: %v", startPath)
+	}
+
+	if !strings.HasPrefix(callbackPath, "/") {
+		return nil, fmt.Errorf("invalid callback path: %v", callbackPath)
 	}
 
-	var (
-		resCh = make(chan *ims.TokenResponse)
-		errCh = make(chan error)
-	)
+	srv := &Server{
+		resCh:      make(chan *ims.TokenResponse, 1),
+		errCh:      make(chan error, 1),
+		doneCh:     make(chan struct{}),
+		shutdownCh: make(chan struct{}),
+	}
+
+	attempts := newAttempts(cfg.UsePKCE)
 
 	result := &resultHandler{
-		successHandler: cfg.OnSuccess,
-		failureHandler: cfg.OnError,
-		resCh:          resCh,
-		errCh:          errCh,
+		successHandler:  cfg.OnSuccess,
+		failureHandler:  cfg.OnError,
+		successTemplate: cfg.SuccessTemplate,
+		errorTemplate:   cfg.ErrorTemplate,
+		productName:     cfg.ProductName,
+		startPath:       startPath,
+		deliverResult:   srv.deliverResult,
+		deliverError:    srv.deliverError,
+	}
+
+	if cfg.Client != nil && cfg.ShowEmail {
+		result.profile = cfg.Client
 	}
 
 	route := &routeMiddleware{
-		redirect: &redirectMiddleware{
-			client:       cfg.Client,
-			clientID:     cfg.ClientID,
-			scope:        cfg.Scope,
-			state:        state,
-			redirectURI:  cfg.RedirectURI,
-			next:         result,
-			codeVerifier: codeVerifier,
-			resource:     cfg.Resource,
-		},
-
-		callback: &callbackMiddleware{
-			client:       cfg.Client,
-			clientID:     cfg.ClientID,
-			clientSecret: cfg.ClientSecret,
-			scope:        cfg.Scope,
-			state:        state,
-			next:         result,
-			codeVerifier: codeVerifier,
-		},
-	}
-
-	server := &http.Server{
+		startPath:        startPath,
+		callbackPath:     callbackPath,
+		completed:        srv.completed,
+		completedHandler: http.HandlerFunc(serveCompleted),
+		redirect:         newRedirectMiddleware(cfg, attempts, result),
+		callback:         newCallbackMiddleware(cfg, attempts, result, retryHandler(startPath)),
+	}
+
+	srv.server = &http.Server{
 		Handler: route,
 	}
 
-	return &Server{
-		server: server,
-		resCh:  resCh,
-		errCh:  errCh,
-	}, nil
+	return srv, nil
 }
 
 // Serve make the server listen to the provided listener.
@@ -149,40 +239,145 @@ func (s *Server) Serve(lst net.Listener) error {
 // Response(). When closing the server, this method has the same semantics of
 // http.Server.Shutdown.
 func (s *Server) Shutdown(ctx context.Context) error {
-	defer close(s.errCh)
-	defer close(s.resCh)
+	err := s.server.Shutdown(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return s.server.Shutdown(ctx)
+	if !s.closed {
+		s.closed = true
+		close(s.shutdownCh)
+		close(s.errCh)
+		close(s.resCh)
+	}
+
+	return err
 }
 
-// Error returns a channel that can be listened to for error conditions.
+// Error returns a channel that can be listened to for error conditions. Every
+// failed login attempt sends its error, but the channel only buffers the most
+// recent one, so handlers never block on a missing reader.
 func (s *Server) Error() <-chan error {
 	return s.errCh
 }
 
 // Response returns a channel that can be listened to for a successful login.
+// The response is buffered.
 func (s *Server) Response() <-chan *ims.TokenResponse {
 	return s.resCh
 }
 
-func randomState() (string, error) {
-	binaryData := make([]byte, 128)
+// Wait waits for a login attempt to succeed and returns its response. Failed
+// attempts don't end the wait, since the user can try again from the browser.
+// If the context is done or the server is shut down first, Wait returns a
+// *WaitError reporting the error of the last failed attempt, if any.
+func (s *Server) Wait(ctx context.Context) (*ims.TokenResponse, error) {
+	select {
+	case <-s.doneCh:
+		return s.res, nil
+	case <-ctx.Done():
+		return s.waitResult(ctx.Err())
+	case <-s.shutdownCh:
+		return s.waitResult(http.ErrServerClosed)
+	}
+}
+
+// waitResult returns the response if the login succeeded concurrently, or a
+// *WaitError wrapping err otherwise.
+func (s *Server) waitResult(err error) (*ims.TokenResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if _, err := rand.Read(binaryData); err != nil {
-		return "", fmt.Errorf("error generating state parameter: %v", err)
+	if s.completed() {
+		return s.res, nil
 	}
 
-	return base64.StdEncoding.EncodeToString(binaryData), nil
+	return nil, &WaitError{
+		Err:            err,
+		LastAttemptErr: s.lastErr,
+	}
 }
 
-// Returns a random code verifier parameter for PKCE
-// https://datatracker.ietf.org/doc/html/rfc7636#section-4.1
-func randomCodeVerifier() (string, error) {
-	binaryData := make([]byte, 32)
+// completed reports whether a login attempt succeeded.
+func (s *Server) completed() bool {
+	select {
+	case <-s.doneCh:
+		return true
+	default:
+		return false
+	}
+}
 
-	if _, err := rand.Read(binaryData); err != nil {
-		return "", fmt.Errorf("error generating code verifier parameter: %v", err)
+// deliverResult records the response of a successful attempt. It returns
+// false if another attempt already succeeded.
+func (s *Server) deliverResult(res *ims.TokenResponse) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.completed() {
+		return false
 	}
 
-	return base64.RawURLEncoding.EncodeToString(binaryData), nil
+	s.res = res
+	close(s.doneCh)
+
+	if !s.closed {
+		s.resCh <- res
+	}
+
+	return true
+}
+
+// deliverError records the error of a failed attempt. It returns false if
+// another attempt already succeeded.
+func (s *Server) deliverError(err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.completed() {
+		return false
+	}
+
+	s.lastErr = err
+
+	if s.closed {
+		return true
+	}
+
+	// Replace the error not read yet, if any.
+	select {
+	case s.errCh <- err:
+	default:
+		select {
+		case <-s.errCh:
+		default:
+		}
+		s.errCh <- err
+	}
+
+	return true
+}
+
+// WaitError is the error returned by Server.Wait when no login attempt
+// succeeded.
+type WaitError struct {
+	// Err is why the wait ended: the error of the context, or
+	// http.ErrServerClosed if the server was shut down.
+	Err error
+	// LastAttemptErr is the error of the last failed attempt, if any.
+	LastAttemptErr error
+}
+
+func (e *WaitError) Error() string {
+	if e.LastAttemptErr != nil {
+		return fmt.Sprintf("%v, last attempt failed: %v", e.Err, e.LastAttemptErr)
+	}
+	return e.Err.Error()
+}
+
+func (e *WaitError) Unwrap() []error {
+	if e.LastAttemptErr != nil {
+		return []error{e.Err, e.LastAttemptErr}
+	}
+	return []error{e.Err}
 }
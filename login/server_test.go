@@ -12,11 +12,14 @@ package login_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -126,6 +129,7 @@ func TestServerError(t *testing.T) {
 	mux.HandleFunc("/ims/authorize/v1", func(w http.ResponseWriter, r *http.Request) {
 		v := url.Values{}
 		v.Add("error", "error")
+		v.Add("state", r.URL.Query().Get("state"))
 
 		u := url.URL{
 			Host:     fmt.Sprintf("localhost:%d", port(lst)),
@@ -190,3 +194,335 @@ func TestServerError(t *testing.T) {
 func port(lst net.Listener) int {
 	return lst.Addr().(*net.TCPAddr).Port
 }
+
+func TestNewServerInvalidRedirectURI(t *testing.T) {
+	client, err := ims.NewClient(&ims.ClientConfig{
+		URL: "http://ims.endpoint",
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := login.NewServer(&login.ServerConfig{
+		Client:      client,
+		ClientID:    "client-id",
+		RedirectURI: "http://localhost:8000/callback",
+	}); err != nil {
+		t.Fatalf("create server: %v", err)
+	}
+
+	_, err = login.NewServer(&login.ServerConfig{
+		Client:      client,
+		ClientID:    "client-id",
+		RedirectURI: "http://example.com/callback",
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if err.Error() != `invalid redirect URI "http://example.com/callback": HTTPS required for non-loopback host example.com` {
+		t.Fatalf("invalid error: %v", err)
+	}
+}
+
+func TestServerPaths(t *testing.T) {
+	lst, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	backend := runIMSServer(t, "")
+	defer backend.Close()
+
+	client, err := ims.NewClient(&ims.ClientConfig{
+		URL: backend.URL,
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port(lst))
+
+	server, err := login.NewServer(&login.ServerConfig{
+		Client:       client,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		Scope:        []string{"a", "b"},
+		RedirectURI:  base + "/callback",
+		StartPath:    "/login",
+	})
+	if err != nil {
+		t.Fatalf("create server: %v", err)
+	}
+
+	go func() {
+		if err := server.Serve(lst); err != http.ErrServerClosed {
+			t.Errorf("serve: %v", err)
+		}
+	}()
+
+	get := func(path string) (int, string) {
+		res, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("get %v: %v", path, err)
+		}
+		defer func() { _ = res.Body.Close() }()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+
+		return res.StatusCode, string(body)
+	}
+
+	if code, _ := get("/favicon.ico"); code != http.StatusNotFound {
+		t.Fatalf("invalid status code for stray request: %v", code)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if code, body := get("/login"); code != http.StatusOK || !strings.Contains(body, "You are logged in") {
+			t.Errorf("invalid login response: %v: %v", code, body)
+		}
+	}()
+
+	select {
+	case <-server.Response():
+		// Login completed.
+	case err := <-server.Error():
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	<-done
+
+	// A reload doesn't start a new login.
+
+	if code, body := get("/login"); code != http.StatusOK || body != "Login already completed. You can close this window." {
+		t.Fatalf("invalid response after completion: %v: %v", code, body)
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestServerRetry(t *testing.T) {
+	lst, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	// The first attempt is denied, the second one succeeds.
+
+	var (
+		mux    = http.NewServeMux()
+		states []string
+	)
+
+	mux.HandleFunc("/ims/authorize/v1", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		states = append(states, q.Get("state"))
+
+		v := url.Values{}
+		v.Set("state", q.Get("state"))
+
+		if len(states) == 1 {
+			v.Set("error", "access_denied")
+		} else {
+			v.Set("code", "code")
+		}
+
+		http.Redirect(w, r, q.Get("redirect_uri")+"?"+v.Encode(), http.StatusFound)
+	})
+
+	mux.HandleFunc("/ims/token/v2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"access_token": "access-token", "expires_in": 3600}`)
+	})
+
+	backend := httptest.NewServer(mux)
+	defer backend.Close()
+
+	client, err := ims.NewClient(&ims.ClientConfig{
+		URL: backend.URL,
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port(lst))
+
+	server, err := login.NewServer(&login.ServerConfig{
+		Client:       client,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		Scope:        []string{"openid"},
+		RedirectURI:  base + "/",
+		UsePKCE:      true,
+	})
+	if err != nil {
+		t.Fatalf("create server: %v", err)
+	}
+
+	go func() {
+		if err := server.Serve(lst); err != http.ErrServerClosed {
+			t.Errorf("serve: %v", err)
+		}
+	}()
+
+	for i := 0; i < 2; i++ {
+		res, err := http.Get(base + "/")
+		if err != nil {
+			t.Fatalf("perform request: %v", err)
+		}
+		_ = res.Body.Close()
+	}
+
+	if len(states) != 2 || states[0] == states[1] {
+		t.Fatalf("state reused: %v", states)
+	}
+
+	res, err := server.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	if res.AccessToken != "access-token" {
+		t.Fatalf("invalid access token: %v", res.AccessToken)
+	}
+
+	// Once the login succeeded, Wait returns its response even if the
+	// context is done or the server is shut down.
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 100; i++ {
+		if res, err := server.Wait(ctx); err != nil || res == nil || res.AccessToken != "access-token" {
+			t.Fatalf("invalid result with done context: %v, %v", res, err)
+		}
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if res, err := server.Wait(context.Background()); err != nil || res == nil || res.AccessToken != "access-token" {
+			t.Fatalf("invalid result after shutdown: %v, %v", res, err)
+		}
+	}
+}
+
+func TestServerShowEmail(t *testing.T) {
+	for _, showEmail := range []bool{false, true} {
+		lst, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+
+		var (
+			mux           = http.NewServeMux()
+			profileCalled bool
+		)
+
+		mux.HandleFunc("/ims/authorize/v1", func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+
+			v := url.Values{}
+			v.Set("state", q.Get("state"))
+			v.Set("code", "code")
+
+			http.Redirect(w, r, q.Get("redirect_uri")+"?"+v.Encode(), http.StatusFound)
+		})
+
+		mux.HandleFunc("/ims/token/v2", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, `{"access_token": "access-token", "expires_in": 3600}`)
+		})
+
+		mux.HandleFunc("/ims/profile/v1", func(w http.ResponseWriter, r *http.Request) {
+			profileCalled = true
+			_, _ = fmt.Fprint(w, `{"email": "user@example.com"}`)
+		})
+
+		backend := httptest.NewServer(mux)
+
+		client, err := ims.NewClient(&ims.ClientConfig{
+			URL: backend.URL,
+		})
+		if err != nil {
+			t.Fatalf("create client: %v", err)
+		}
+
+		base := fmt.Sprintf("http://127.0.0.1:%d", port(lst))
+
+		server, err := login.NewServer(&login.ServerConfig{
+			Client:       client,
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			Scope:        []string{"openid"},
+			RedirectURI:  base + "/",
+			ShowEmail:    showEmail,
+		})
+		if err != nil {
+			t.Fatalf("create server: %v", err)
+		}
+
+		go func() {
+			if err := server.Serve(lst); err != http.ErrServerClosed {
+				t.Errorf("serve: %v", err)
+			}
+		}()
+
+		res, err := http.Get(base + "/")
+		if err != nil {
+			t.Fatalf("perform request: %v", err)
+		}
+
+		body, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+
+		if err := server.Shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown: %v", err)
+		}
+
+		backend.Close()
+
+		if profileCalled != showEmail {
+			t.Fatalf("profile read: %v, show email: %v", profileCalled, showEmail)
+		}
+		if strings.Contains(string(body), "user@example.com") != showEmail {
+			t.Fatalf("invalid success page with show email %v: %s", showEmail, body)
+		}
+	}
+}
+
+func TestServerWaitError(t *testing.T) {
+	server, err := login.NewServer(&login.ServerConfig{})
+	if err != nil {
+		t.Fatalf("create server: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = server.Wait(ctx)
+
+	var waitErr *login.WaitError
+	if !errors.As(err, &waitErr) || !errors.Is(err, context.DeadlineExceeded) || waitErr.LastAttemptErr != nil {
+		t.Fatalf("invalid error: %v", err)
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if _, err := server.Wait(context.Background()); !errors.Is(err, http.ErrServerClosed) {
+		t.Fatalf("invalid error after shutdown: %v", err)
+	}
+}
@@ -0,0 +1,528 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultWebCookieName    = "ims_login"
+	defaultWebLoginLifetime = 10 * time.Minute
+	// maxWebLoginCookies bounds the number of logins in progress in the same
+	// browser, so that abandoned logins don't accumulate cookies until the
+	// browser evicts other cookies of the application.
+	maxWebLoginCookies = 5
+)
+
+type webBackend interface {
+	AuthorizeURL(cfg *ims.AuthorizeURLConfig) (string, error)
+	TokenWithContext(ctx context.Context, r *ims.TokenRequest) (*ims.TokenResponse, error)
+}
+
+// WebConfig is the configuration for a WebLogin.
+type WebConfig struct {
+	// The IMS client. This field is required.
+	Client *ims.Client
+	// The client ID. This field is required.
+	ClientID string
+	// The client secret.
+	ClientSecret string
+	// The method used to authenticate the client to the token endpoint. If
+	// provided, ClientSecret must be empty.
+	ClientAuth ims.ClientAuthenticator
+	// List of scopes to request.
+	Scope []string
+	// The absolute URL where the handler returned by CallbackHandler is
+	// mounted. This field is required.
+	RedirectURI string
+	// Resource is the RFC 8707 resource indicator(s) for audience-restricted
+	// tokens. Optional.
+	Resource []string
+	// The AES keys encrypting the login cookies, 16, 24 or 32 bytes long
+	// each. The first key encrypts new cookies, while every key is used to
+	// decrypt them. At least one key is required.
+	CookieKeys [][]byte
+	// The prefix of the name of the login cookies. If not provided, it
+	// defaults to "ims_login".
+	CookieName string
+	// The path of the login cookies. It must include the path of
+	// RedirectURI. If not provided, it defaults to "/".
+	CookiePath string
+	// Send the login cookies over plain HTTP too. Only meant for local
+	// development.
+	InsecureCookie bool
+	// How long a login can take, from the redirect to IMS to the callback.
+	// If not provided, it defaults to ten minutes.
+	Lifetime time.Duration
+	// OnLogin is called with the tokens of the user and the callback
+	// request, typically to start a session. The user is then redirected to
+	// the URL where the login started. If OnLogin returns an error, it must
+	// not write to the response. This field is required.
+	OnLogin func(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) error
+	// A custom handler for sending an error response to the client. If not
+	// provided, a default response is sent.
+	OnError http.Handler
+}
+
+// WebLogin performs user logins in a web application, where many users log in
+// concurrently. Every login gets its own state, PKCE code verifier and nonce,
+// stored in a short-lived encrypted cookie in the browser of the user, so that
+// no server-side storage is needed and any instance of the application can
+// handle the callback. At most five logins can be in progress in the same
+// browser: starting another one drops the oldest.
+//
+// The handler returned by LoginHandler starts the login. It accepts an
+// optional "return_to" query parameter, the local path the user is redirected
+// to after the login. The handler returned by CallbackHandler must be mounted
+// at RedirectURI.
+type WebLogin struct {
+	client         webBackend
+	clientID       string
+	clientSecret   string
+	clientAuth     ims.ClientAuthenticator
+	scope          []string
+	redirectURI    string
+	resource       []string
+	cookieAEADs    []cipher.AEAD
+	cookieName     string
+	cookiePath     string
+	insecureCookie bool
+	lifetime       time.Duration
+	onLogin        func(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) error
+	onError        http.Handler
+}
+
+// webLoginCookie is the content of the login cookie. It contains the code
+// verifier, so it is encrypted and not only signed.
+type webLoginCookie struct {
+	State        string `json:"s"`
+	CodeVerifier string `json:"v"`
+	Nonce        string `json:"n"`
+	ReturnURL    string `json:"r"`
+	ExpiresAt    int64  `json:"e"`
+}
+
+// NewWebLogin creates a new WebLogin for the provided WebConfig.
+func NewWebLogin(cfg *WebConfig) (*WebLogin, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("missing client")
+	}
+
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("missing client ID")
+	}
+
+	if cfg.ClientSecret != "" && cfg.ClientAuth != nil {
+		return nil, fmt.Errorf("client secret and client authenticator are mutually exclusive")
+	}
+
+	if cfg.RedirectURI == "" {
+		return nil, fmt.Errorf("missing redirect URI")
+	}
+
+	if err := ims.ValidateRedirectURI(cfg.RedirectURI, nil); err != nil {
+		return nil, err
+	}
+
+	if cfg.OnLogin == nil {
+		return nil, fmt.Errorf("missing login hook")
+	}
+
+	if len(cfg.CookieKeys) == 0 {
+		return nil, fmt.Errorf("missing cookie keys")
+	}
+
+	if cfg.Lifetime < 0 {
+		return nil, fmt.Errorf("invalid lifetime: %v", cfg.Lifetime)
+	}
+
+	cookieName := cfg.CookieName
+	if cookieName == "" {
+		cookieName = defaultWebCookieName
+	}
+
+	cookiePath := cfg.CookiePath
+	if cookiePath == "" {
+		cookiePath = "/"
+	}
+
+	lifetime := cfg.Lifetime
+	if lifetime == 0 {
+		lifetime = defaultWebLoginLifetime
+	}
+
+	cookieAEADs := make([]cipher.AEAD, len(cfg.CookieKeys))
+
+	for i, key := range cfg.CookieKeys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie key at index %d: %v", i, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie key at index %d: %v", i, err)
+		}
+
+		cookieAEADs[i] = aead
+	}
+
+	onError := cfg.OnError
+	if onError == nil {
+		onError = http.HandlerFunc(webErrorHandler)
+	}
+
+	return &WebLogin{
+		client:         cfg.Client,
+		clientID:       cfg.ClientID,
+		clientSecret:   cfg.ClientSecret,
+		clientAuth:     cfg.ClientAuth,
+		scope:          cfg.Scope,
+		redirectURI:    cfg.RedirectURI,
+		resource:       cfg.Resource,
+		cookieAEADs:    cookieAEADs,
+		cookieName:     cookieName,
+		cookiePath:     cookiePath,
+		insecureCookie: cfg.InsecureCookie,
+		lifetime:       lifetime,
+		onLogin:        cfg.OnLogin,
+		onError:        onError,
+	}, nil
+}
+
+// LoginHandler returns the handler starting a login.
+func (l *WebLogin) LoginHandler() http.Handler {
+	return http.HandlerFunc(l.serveLogin)
+}
+
+// CallbackHandler returns the handler completing a login.
+func (l *WebLogin) CallbackHandler() http.Handler {
+	return http.HandlerFunc(l.serveCallback)
+}
+
+func (l *WebLogin) serveLogin(w http.ResponseWriter, r *http.Request) {
+	state, err := ims.NewState()
+	if err != nil {
+		serveError(l.onError, w, r, err)
+		return
+	}
+
+	pkce, err := ims.NewPKCE()
+	if err != nil {
+		serveError(l.onError, w, r, err)
+		return
+	}
+
+	nonce, err := ims.NewState()
+	if err != nil {
+		serveError(l.onError, w, r, fmt.Errorf("generate nonce: %v", err))
+		return
+	}
+
+	expiresAt := time.Now().Add(l.lifetime)
+
+	cookieName := l.cookieNameFor(state)
+
+	value, err := l.encryptCookie(cookieName, &webLoginCookie{
+		State:        state,
+		CodeVerifier: pkce.CodeVerifier,
+		Nonce:        nonce,
+		ReturnURL:    localReturnURL(r.URL.Query().Get("return_to")),
+		ExpiresAt:    expiresAt.Unix(),
+	})
+	if err != nil {
+		serveError(l.onError, w, r, err)
+		return
+	}
+
+	authorizeURL, err := l.client.AuthorizeURL(&ims.AuthorizeURLConfig{
+		ClientID:     l.clientID,
+		GrantType:    ims.GrantTypeCode,
+		Scope:        l.scope,
+		RedirectURI:  l.redirectURI,
+		State:        state,
+		CodeVerifier: pkce.CodeVerifier,
+		Nonce:        nonce,
+		Resource:     l.resource,
+	})
+	if err != nil {
+		serveError(l.onError, w, r, fmt.Errorf("generate authorization URL: %v", err))
+		return
+	}
+
+	l.pruneCookies(w, r)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    value,
+		Path:     l.cookiePath,
+		Expires:  expiresAt,
+		MaxAge:   int(l.lifetime / time.Second),
+		Secure:   !l.insecureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authorizeURL, http.StatusFound)
+}
+
+func (l *WebLogin) serveCallback(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	if urlErr := values.Get("error"); urlErr != "" {
+		serveError(l.onError, w, r, fmt.Errorf("backend error: %s", urlErr))
+		return
+	}
+
+	state := values.Get("state")
+	if state == "" {
+		serveError(l.onError, w, r, fmt.Errorf("missing state parameter"))
+		return
+	}
+
+	cookie, err := r.Cookie(l.cookieNameFor(state))
+	if err != nil {
+		serveError(l.onError, w, r, fmt.Errorf("missing login cookie"))
+		return
+	}
+
+	// The cookie is consumed by the first callback, successful or not.
+	l.clearCookie(w, cookie.Name)
+
+	login, err := l.decryptCookie(cookie.Name, cookie.Value)
+	if err != nil {
+		serveError(l.onError, w, r, fmt.Errorf("invalid login cookie: %v", err))
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(login.State), []byte(state)) != 1 {
+		serveError(l.onError, w, r, fmt.Errorf("invalid state parameter"))
+		return
+	}
+
+	code := values.Get("code")
+	if code == "" {
+		serveError(l.onError, w, r, fmt.Errorf("missing code parameter"))
+		return
+	}
+
+	res, err := l.client.TokenWithContext(r.Context(), &ims.TokenRequest{
+		Code:         code,
+		ClientID:     l.clientID,
+		ClientSecret: l.clientSecret,
+		ClientAuth:   l.clientAuth,
+		Scope:        l.scope,
+		CodeVerifier: login.CodeVerifier,
+	})
+	if err != nil {
+		serveError(l.onError, w, r, fmt.Errorf("obtaining access token: %v", err))
+		return
+	}
+
+	if err := verifyNonce(res.IDToken, login.Nonce); err != nil {
+		serveError(l.onError, w, r, err)
+		return
+	}
+
+	if err := l.onLogin(w, r, res); err != nil {
+		serveError(l.onError, w, r, fmt.Errorf("login hook: %v", err))
+		return
+	}
+
+	http.Redirect(w, r, login.ReturnURL, http.StatusFound)
+}
+
+// cookieNameFor returns the name of the cookie of the login with the given
+// state. Every login has its own cookie, so that concurrent logins in the same
+// browser don't overwrite each other.
+func (l *WebLogin) cookieNameFor(state string) string {
+	h := sha256.Sum256([]byte(state))
+	return l.cookieName + "_" + hex.EncodeToString(h[:8])
+}
+
+// isLoginCookie reports whether the cookie is a login cookie, whatever its
+// state.
+func (l *WebLogin) isLoginCookie(c *http.Cookie) bool {
+	prefix := l.cookieName + "_"
+
+	if !strings.HasPrefix(c.Name, prefix) {
+		return false
+	}
+
+	_, err := hex.DecodeString(c.Name[len(prefix):])
+
+	return err == nil && len(c.Name) == len(prefix)+16
+}
+
+// pruneCookies clears the login cookies that are invalid or expired, and the
+// oldest ones if there is no room left for a new login.
+func (l *WebLogin) pruneCookies(w http.ResponseWriter, r *http.Request) {
+	type loginCookie struct {
+		name      string
+		expiresAt int64
+	}
+
+	var valid []loginCookie
+
+	for _, c := range r.Cookies() {
+		if !l.isLoginCookie(c) {
+			continue
+		}
+
+		login, err := l.decryptCookie(c.Name, c.Value)
+		if err != nil {
+			l.clearCookie(w, c.Name)
+			continue
+		}
+
+		valid = append(valid, loginCookie{c.Name, login.ExpiresAt})
+	}
+
+	// Cookies started in the same second keep the order of the request.
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].expiresAt < valid[j].expiresAt
+	})
+
+	for len(valid) >= maxWebLoginCookies {
+		l.clearCookie(w, valid[0].name)
+		valid = valid[1:]
+	}
+}
+
+func (l *WebLogin) clearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     l.cookiePath,
+		MaxAge:   -1,
+		Secure:   !l.insecureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// encryptCookie returns the value of the login cookie with the given name. The
+// nonce of AES-GCM is followed by the sealed content. The name of the cookie
+// is authenticated too, so that a value can't be moved to another cookie.
+func (l *WebLogin) encryptCookie(name string, c *webLoginCookie) (string, error) {
+	plaintext, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("encode login cookie: %v", err)
+	}
+
+	aead := l.cookieAEADs[0]
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+func (l *WebLogin) decryptCookie(name, value string) (*webLoginCookie, error) {
+	ciphertext, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %v", err)
+	}
+
+	var plaintext []byte
+
+	for _, aead := range l.cookieAEADs {
+		if len(ciphertext) < aead.NonceSize() {
+			return nil, fmt.Errorf("ciphertext too short")
+		}
+
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+
+		if plaintext, err = aead.Open(nil, nonce, sealed, []byte(name)); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("no key can decrypt the cookie")
+	}
+
+	var c webLoginCookie
+
+	if err := json.Unmarshal(plaintext, &c); err != nil {
+		return nil, fmt.Errorf("decode: %v", err)
+	}
+
+	if !time.Now().Before(time.Unix(c.ExpiresAt, 0)) {
+		return nil, fmt.Errorf("expired")
+	}
+
+	return &c, nil
+}
+
+// verifyNonce checks that the ID token, if any, is bound to the nonce sent in
+// the authorization request. The signature of the ID token isn't verified,
+// because the token was received directly from IMS.
+func verifyNonce(idToken, nonce string) error {
+	if idToken == "" {
+		return nil
+	}
+
+	var claims struct {
+		jwt.RegisteredClaims
+		Nonce string `json:"nonce"`
+	}
+
+	if _, _, err := jwt.NewParser().ParseUnverified(idToken, &claims); err != nil {
+		return fmt.Errorf("parse ID token: %v", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return fmt.Errorf("invalid ID token nonce")
+	}
+
+	return nil
+}
+
+// localReturnURL returns the URL if it is a local path, or "/" otherwise, so
+// that the login can't be used as an open redirect.
+func localReturnURL(s string) string {
+	if s == "" || !strings.HasPrefix(s, "/") || strings.HasPrefix(s, "//") || strings.HasPrefix(s, "/\\") {
+		return "/"
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return "/"
+	}
+
+	return s
+}
+
+func webErrorHandler(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "Login failed.", http.StatusBadRequest)
+}
@@ -0,0 +1,302 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package login_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/adobe/ims-go/login"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// webIMSServer returns a fake IMS token endpoint. The nonce of the returned ID
+// token is read from the nonce pointer, and the received code verifier is
+// stored in verifier.
+func webIMSServer(t *testing.T, nonce *string, verifier *string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+
+		*verifier = r.PostForm.Get("code_verifier")
+
+		idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"nonce": *nonce}).SignedString([]byte("key"))
+		if err != nil {
+			t.Fatalf("sign ID token: %v", err)
+		}
+
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token-" + r.PostForm.Get("code"),
+			"id_token":     idToken,
+			"expires_in":   3600,
+		}); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+}
+
+func newWebLogin(t *testing.T, imsURL string, onLogin func(http.ResponseWriter, *http.Request, *ims.TokenResponse) error) *login.WebLogin {
+	t.Helper()
+
+	client, err := ims.NewClient(&ims.ClientConfig{URL: imsURL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	l, err := login.NewWebLogin(&login.WebConfig{
+		Client:      client,
+		ClientID:    "client-id",
+		Scope:       []string{"openid"},
+		RedirectURI: "https://app.example.com/callback",
+		CookieKeys:  [][]byte{bytes.Repeat([]byte("k"), 32)},
+		OnLogin:     onLogin,
+	})
+	if err != nil {
+		t.Fatalf("create web login: %v", err)
+	}
+
+	return l
+}
+
+// startWebLogin calls the login handler and returns the authorization URL and
+// the login cookie.
+func startWebLogin(t *testing.T, l *login.WebLogin, target string) (*url.URL, *http.Cookie) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	l.LoginHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("invalid status code: %v", w.Code)
+	}
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("parse location: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("invalid cookies: %v", cookies)
+	}
+	if !cookies[0].HttpOnly || !cookies[0].Secure || cookies[0].SameSite != http.SameSiteLaxMode {
+		t.Fatalf("invalid cookie attributes: %v", cookies[0])
+	}
+
+	return location, cookies[0]
+}
+
+func callbackRequest(state, code string, cookies ...*http.Cookie) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/callback?state=%s&code=%s", url.QueryEscape(state), code), nil)
+
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+
+	return r
+}
+
+func TestWebLogin(t *testing.T) {
+	var nonce, verifier string
+
+	s := webIMSServer(t, &nonce, &verifier)
+	defer s.Close()
+
+	var tokens []string
+
+	l := newWebLogin(t, s.URL, func(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) error {
+		tokens = append(tokens, res.AccessToken)
+		return nil
+	})
+
+	// Two concurrent logins in the same browser.
+
+	first, firstCookie := startWebLogin(t, l, "/login?return_to=/dashboard%3Ftab%3D1")
+	second, secondCookie := startWebLogin(t, l, "/login")
+
+	if first.Query().Get("state") == second.Query().Get("state") {
+		t.Fatalf("state reused")
+	}
+	if firstCookie.Name == secondCookie.Name {
+		t.Fatalf("cookie reused")
+	}
+
+	for _, tt := range []struct {
+		authorizeURL *url.URL
+		code         string
+		wantLocation string
+	}{
+		{second, "b", "/"},
+		{first, "a", "/dashboard?tab=1"},
+	} {
+		q := tt.authorizeURL.Query()
+		nonce = q.Get("nonce")
+
+		w := httptest.NewRecorder()
+		l.CallbackHandler().ServeHTTP(w, callbackRequest(q.Get("state"), tt.code, firstCookie, secondCookie))
+
+		if w.Code != http.StatusFound {
+			t.Fatalf("invalid status code: %v: %v", w.Code, w.Body.String())
+		}
+		if v := w.Header().Get("Location"); v != tt.wantLocation {
+			t.Fatalf("invalid location: %v", v)
+		}
+		if ims.CodeChallenge(verifier) != q.Get("code_challenge") {
+			t.Fatalf("invalid code verifier")
+		}
+	}
+
+	if len(tokens) != 2 || tokens[0] != "access-token-b" || tokens[1] != "access-token-a" {
+		t.Fatalf("invalid tokens: %v", tokens)
+	}
+}
+
+func TestWebLoginCallbackErrors(t *testing.T) {
+	var nonce, verifier string
+
+	s := webIMSServer(t, &nonce, &verifier)
+	defer s.Close()
+
+	l := newWebLogin(t, s.URL, func(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) error {
+		t.Fatalf("unexpected login")
+		return nil
+	})
+
+	authorizeURL, cookie := startWebLogin(t, l, "/login")
+	state := authorizeURL.Query().Get("state")
+
+	tampered := *cookie
+	tampered.Value = "x" + cookie.Value
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		nonce   string
+	}{
+		{
+			name:    "missing cookie",
+			request: callbackRequest(state, "code"),
+		},
+		{
+			name:    "tampered cookie",
+			request: callbackRequest(state, "code", &tampered),
+		},
+		{
+			name:    "invalid nonce",
+			request: callbackRequest(state, "code", cookie),
+			nonce:   "other-nonce",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce = tt.nonce
+
+			w := httptest.NewRecorder()
+			l.CallbackHandler().ServeHTTP(w, tt.request)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("invalid status code: %v", w.Code)
+			}
+		})
+	}
+}
+
+func TestWebLoginCookieEncrypted(t *testing.T) {
+	l := newWebLogin(t, "http://ims.example.com", func(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) error {
+		return nil
+	})
+
+	authorizeURL, cookie := startWebLogin(t, l, "/login")
+
+	value, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		t.Fatalf("decode cookie: %v", err)
+	}
+
+	if strings.Contains(string(value), authorizeURL.Query().Get("state")) {
+		t.Fatalf("cookie not encrypted")
+	}
+}
+
+func TestWebLoginCookieLimit(t *testing.T) {
+	l := newWebLogin(t, "http://ims.example.com", func(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) error {
+		return nil
+	})
+
+	var live []*http.Cookie
+
+	for i := 0; i < 5; i++ {
+		_, cookie := startWebLogin(t, l, "/login")
+		live = append(live, cookie)
+	}
+
+	// The sixth login drops the oldest one.
+
+	r := httptest.NewRequest(http.MethodGet, "/login", nil)
+	for _, c := range live {
+		r.AddCookie(c)
+	}
+
+	w := httptest.NewRecorder()
+	l.LoginHandler().ServeHTTP(w, r)
+
+	var cleared, added []string
+
+	for _, c := range w.Result().Cookies() {
+		if c.MaxAge < 0 {
+			cleared = append(cleared, c.Name)
+		} else {
+			added = append(added, c.Name)
+		}
+	}
+
+	if len(cleared) != 1 || cleared[0] != live[0].Name {
+		t.Fatalf("invalid cleared cookies: %v", cleared)
+	}
+	if len(added) != 1 {
+		t.Fatalf("invalid added cookies: %v", added)
+	}
+}
+
+func TestWebLoginOpenRedirect(t *testing.T) {
+	var nonce, verifier string
+
+	s := webIMSServer(t, &nonce, &verifier)
+	defer s.Close()
+
+	l := newWebLogin(t, s.URL, func(w http.ResponseWriter, r *http.Request, res *ims.TokenResponse) error {
+		return nil
+	})
+
+	for _, returnTo := range []string{"https://evil.example.com", "//evil.example.com", "/\\evil.example.com"} {
+		authorizeURL, cookie := startWebLogin(t, l, "/login?return_to="+url.QueryEscape(returnTo))
+		nonce = authorizeURL.Query().Get("nonce")
+
+		w := httptest.NewRecorder()
+		l.CallbackHandler().ServeHTTP(w, callbackRequest(authorizeURL.Query().Get("state"), "code", cookie))
+
+		if v := w.Header().Get("Location"); v != "/" {
+			t.Fatalf("invalid location for %v: %v", returnTo, v)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	backChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+	defaultLogoutTokenMaxAge = 5 * time.Minute
+	defaultKeysRefreshDelay  = time.Minute
+	// logoutTokenLeeway tolerates the clock skew between IMS and this
+	// server when checking the issue time of the tokens.
+	logoutTokenLeeway = time.Minute
+)
+
+// LogoutToken is a verified back-channel logout token.
+type LogoutToken struct {
+	// Subject is the IMS ID of the user logged out. Either Subject or
+	// SessionID is set.
+	Subject string
+	// SessionID is the ID of the IMS session ended. Either Subject or
+	// SessionID is set.
+	SessionID string
+	// JWTID is the unique ID of the token.
+	JWTID string
+	// IssuedAt is when the token was issued.
+	IssuedAt time.Time
+}
+
+// BackChannelLogoutConfig is the configuration for a BackChannelLogout.
+type BackChannelLogoutConfig struct {
+	// Client is the IMS client used to fetch the public keys of IMS. This
+	// field is required.
+	Client *ims.Client
+	// Issuer is the expected issuer of the logout tokens, e.g.
+	// "https://ims-na1.adobelogin.com". This field is required.
+	Issuer string
+	// ClientID is the client ID, expected as the audience of the logout
+	// tokens. This field is required.
+	ClientID string
+	// OnLogout is called for every valid logout token, and should terminate
+	// the sessions matching its subject or session ID. If it returns an
+	// error, the request fails and the token can be delivered again.
+	// ServerStore.Logout implements it for the sessions of a ServerStore.
+	// This field is required.
+	OnLogout func(ctx context.Context, t *LogoutToken) error
+	// MaxAge is how old a logout token can be. The IDs of the tokens are
+	// remembered for this long to reject replayed tokens. They are kept in
+	// memory, so replayed tokens are only rejected by the instance that
+	// received the token first. If not provided, it defaults to five
+	// minutes.
+	MaxAge time.Duration
+}
+
+// BackChannelLogout is an http.Handler receiving the OpenID Connect
+// back-channel logout requests sent by IMS when a user logs out.
+type BackChannelLogout struct {
+	client   *ims.Client
+	issuer   string
+	clientID string
+	onLogout func(ctx context.Context, t *LogoutToken) error
+	maxAge   time.Duration
+
+	keysMu      sync.Mutex
+	keys        map[string]interface{}
+	keysFetched time.Time
+	keysFetch   chan struct{}
+
+	jtisMu sync.Mutex
+	jtis   map[string]time.Time
+}
+
+// NewBackChannelLogout creates a new BackChannelLogout.
+func NewBackChannelLogout(cfg *BackChannelLogoutConfig) (*BackChannelLogout, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("missing client")
+	}
+
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("missing issuer")
+	}
+
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("missing client ID")
+	}
+
+	if cfg.OnLogout == nil {
+		return nil, fmt.Errorf("missing logout hook")
+	}
+
+	if cfg.MaxAge < 0 {
+		return nil, fmt.Errorf("invalid max age: %v", cfg.MaxAge)
+	}
+
+	maxAge := cfg.MaxAge
+	if maxAge == 0 {
+		maxAge = defaultLogoutTokenMaxAge
+	}
+
+	return &BackChannelLogout{
+		client:   cfg.Client,
+		issuer:   cfg.Issuer,
+		clientID: cfg.ClientID,
+		onLogout: cfg.OnLogout,
+		maxAge:   maxAge,
+		jtis:     map[string]time.Time{},
+	}, nil
+}
+
+// ServeHTTP implements http.Handler. It responds with 200 if the logout token
+// is valid and OnLogout succeeds, and with 400 otherwise.
+func (b *BackChannelLogout) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rawToken := r.PostFormValue("logout_token")
+	if rawToken == "" {
+		serveLogoutError(w, "missing logout token")
+		return
+	}
+
+	t, err := b.verify(r.Context(), rawToken)
+	if err != nil {
+		serveLogoutError(w, fmt.Sprintf("invalid logout token: %v", err))
+		return
+	}
+
+	if !b.reserveJTI(t) {
+		serveLogoutError(w, "invalid logout token: replayed token")
+		return
+	}
+
+	if err := b.onLogout(r.Context(), t); err != nil {
+		// Let IMS deliver the token again.
+		b.releaseJTI(t)
+		serveLogoutError(w, fmt.Sprintf("logout failed: %v", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func serveLogoutError(w http.ResponseWriter, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error":             "invalid_request",
+		"error_description": description,
+	})
+}
+
+type logoutClaims struct {
+	jwt.RegisteredClaims
+	SessionID string                     `json:"sid"`
+	Events    map[string]json.RawMessage `json:"events"`
+	Nonce     *string                    `json:"nonce"`
+}
+
+func (b *BackChannelLogout) verify(ctx context.Context, rawToken string) (*LogoutToken, error) {
+	var claims logoutClaims
+
+	_, err := jwt.ParseWithClaims(rawToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return b.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(b.issuer),
+		jwt.WithAudience(b.clientID),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(logoutTokenLeeway),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.IssuedAt == nil {
+		return nil, fmt.Errorf("missing issued at")
+	}
+
+	if time.Since(claims.IssuedAt.Time) > b.maxAge {
+		return nil, fmt.Errorf("issued at %v, too old", claims.IssuedAt.Time.Format(time.RFC3339))
+	}
+
+	if claims.ID == "" {
+		return nil, fmt.Errorf("missing JWT ID")
+	}
+
+	if claims.Subject == "" && claims.SessionID == "" {
+		return nil, fmt.Errorf("missing subject and session ID")
+	}
+
+	event, ok := claims.Events[backChannelLogoutEvent]
+	if !ok {
+		return nil, fmt.Errorf("missing back-channel logout event")
+	}
+
+	var eventObject map[string]interface{}
+
+	if err := json.Unmarshal(event, &eventObject); err != nil || eventObject == nil {
+		return nil, fmt.Errorf("invalid back-channel logout event")
+	}
+
+	if claims.Nonce != nil {
+		return nil, fmt.Errorf("unexpected nonce")
+	}
+
+	return &LogoutToken{
+		Subject:   claims.Subject,
+		SessionID: claims.SessionID,
+		JWTID:     claims.ID,
+		IssuedAt:  claims.IssuedAt.Time,
+	}, nil
+}
+
+// key returns the public key with the given ID. The keys are fetched again
+// when the key is unknown, for instance after a key rotation, but not more
+// often than once a minute. The keys are fetched without holding keysMu, and
+// concurrent requests wait for the fetch in progress instead of starting
+// their own.
+func (b *BackChannelLogout) key(ctx context.Context, kid string) (interface{}, error) {
+	b.keysMu.Lock()
+
+	if key, ok := b.keys[kid]; ok {
+		b.keysMu.Unlock()
+		return key, nil
+	}
+
+	if fetch := b.keysFetch; fetch != nil {
+		b.keysMu.Unlock()
+
+		select {
+		case <-fetch:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		return b.knownKey(kid)
+	}
+
+	if time.Since(b.keysFetched) < defaultKeysRefreshDelay {
+		b.keysMu.Unlock()
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	fetch := make(chan struct{})
+	b.keysFetch = fetch
+
+	b.keysMu.Unlock()
+
+	keys, err := b.fetchKeys(ctx)
+
+	b.keysMu.Lock()
+
+	if err == nil {
+		b.keys = keys
+		b.keysFetched = time.Now()
+	}
+
+	b.keysFetch = nil
+	close(fetch)
+
+	b.keysMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b.knownKey(kid)
+}
+
+func (b *BackChannelLogout) knownKey(kid string) (interface{}, error) {
+	b.keysMu.Lock()
+	defer b.keysMu.Unlock()
+
+	if key, ok := b.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (b *BackChannelLogout) fetchKeys(ctx context.Context) (map[string]interface{}, error) {
+	res, err := b.client.GetKeysWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get keys: %v", err)
+	}
+
+	keys := make(map[string]interface{}, len(res.Keys))
+
+	for i := range res.Keys {
+		key, err := res.Keys[i].PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[res.Keys[i].KeyID] = key
+	}
+
+	return keys, nil
+}
+
+// reserveJTI records the ID of the token, and returns false if it was already
+// recorded. IDs are forgotten once the tokens are too old to be accepted.
+func (b *BackChannelLogout) reserveJTI(t *LogoutToken) bool {
+	b.jtisMu.Lock()
+	defer b.jtisMu.Unlock()
+
+	now := time.Now()
+
+	for jti, expiresAt := range b.jtis {
+		if now.After(expiresAt) {
+			delete(b.jtis, jti)
+		}
+	}
+
+	if _, ok := b.jtis[t.JWTID]; ok {
+		return false
+	}
+
+	b.jtis[t.JWTID] = t.IssuedAt.Add(b.maxAge)
+
+	return true
+}
+
+func (b *BackChannelLogout) releaseJTI(t *LogoutToken) {
+	b.jtisMu.Lock()
+	defer b.jtisMu.Unlock()
+
+	delete(b.jtis, t.JWTID)
+}
@@ -0,0 +1,465 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/adobe/ims-go/session"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testIssuer = "https://ims.example.com"
+
+// keysServer returns a fake IMS serving the public key of key with ID kid.
+func keysServer(t *testing.T, key *rsa.PrivateKey, kid string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ims/keys" {
+			t.Fatalf("invalid path: %v", r.URL.Path)
+		}
+
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kid": kid,
+					"kty": "RSA",
+					"alg": "RS256",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		}); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+}
+
+func logoutClaims(jti string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    testIssuer,
+		"aud":    "client-id",
+		"iat":    time.Now().Unix(),
+		"jti":    jti,
+		"sub":    "user-id",
+		"sid":    "session-id",
+		"events": map[string]interface{}{"http://schemas.openid.net/event/backchannel-logout": map[string]interface{}{}},
+	}
+}
+
+func signLogoutToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	return signed
+}
+
+func postLogoutToken(h http.Handler, token string) *httptest.ResponseRecorder {
+	form := url.Values{"logout_token": {token}}
+
+	r := httptest.NewRequest(http.MethodPost, "/backchannel-logout", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func newBackChannelLogout(t *testing.T, imsURL string, onLogout func(context.Context, *session.LogoutToken) error) *session.BackChannelLogout {
+	t.Helper()
+
+	client, err := ims.NewClient(&ims.ClientConfig{URL: imsURL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	b, err := session.NewBackChannelLogout(&session.BackChannelLogoutConfig{
+		Client:   client,
+		Issuer:   testIssuer,
+		ClientID: "client-id",
+		OnLogout: onLogout,
+	})
+	if err != nil {
+		t.Fatalf("create handler: %v", err)
+	}
+
+	return b
+}
+
+func TestBackChannelLogout(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s := keysServer(t, key, "key-1")
+	defer s.Close()
+
+	var logouts []*session.LogoutToken
+
+	b := newBackChannelLogout(t, s.URL, func(ctx context.Context, lt *session.LogoutToken) error {
+		logouts = append(logouts, lt)
+		return nil
+	})
+
+	token := signLogoutToken(t, key, "key-1", logoutClaims("jti-1"))
+
+	if w := postLogoutToken(b, token); w.Code != http.StatusOK {
+		t.Fatalf("invalid status code: %v: %v", w.Code, w.Body.String())
+	}
+
+	if len(logouts) != 1 || logouts[0].Subject != "user-id" || logouts[0].SessionID != "session-id" || logouts[0].JWTID != "jti-1" {
+		t.Fatalf("invalid logouts: %+v", logouts)
+	}
+
+	// The same token is rejected when replayed.
+
+	if w := postLogoutToken(b, token); w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid status code for replayed token: %v", w.Code)
+	}
+
+	if len(logouts) != 1 {
+		t.Fatalf("replayed token accepted")
+	}
+}
+
+func TestBackChannelLogoutInvalidTokens(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s := keysServer(t, key, "key-1")
+	defer s.Close()
+
+	b := newBackChannelLogout(t, s.URL, func(ctx context.Context, lt *session.LogoutToken) error {
+		t.Fatalf("unexpected logout")
+		return nil
+	})
+
+	tests := []struct {
+		name   string
+		key    *rsa.PrivateKey
+		kid    string
+		modify func(jwt.MapClaims)
+	}{
+		{
+			name: "invalid signature",
+			key:  otherKey,
+			kid:  "key-1",
+		},
+		{
+			name: "unknown key",
+			key:  key,
+			kid:  "key-2",
+		},
+		{
+			name:   "invalid issuer",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		},
+		{
+			name:   "invalid audience",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { c["aud"] = "other-client-id" },
+		},
+		{
+			name:   "missing event",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { delete(c, "events") },
+		},
+		{
+			name:   "missing subject and session ID",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { delete(c, "sub"); delete(c, "sid") },
+		},
+		{
+			name:   "missing JWT ID",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { delete(c, "jti") },
+		},
+		{
+			name:   "nonce",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { c["nonce"] = "nonce" },
+		},
+		{
+			name:   "too old",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { c["iat"] = time.Now().Add(-time.Hour).Unix() },
+		},
+		{
+			name:   "issued in the future",
+			key:    key,
+			kid:    "key-1",
+			modify: func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() },
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := logoutClaims(fmt.Sprintf("jti-%d", i))
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+
+			if w := postLogoutToken(b, signLogoutToken(t, tt.key, tt.kid, claims)); w.Code != http.StatusBadRequest {
+				t.Fatalf("invalid status code: %v", w.Code)
+			}
+		})
+	}
+}
+
+func TestBackChannelLogoutClockSkew(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s := keysServer(t, key, "key-1")
+	defer s.Close()
+
+	b := newBackChannelLogout(t, s.URL, func(ctx context.Context, lt *session.LogoutToken) error {
+		return nil
+	})
+
+	// A token issued slightly in the future, by a clock ahead of ours, is
+	// accepted.
+
+	claims := logoutClaims("jti-1")
+	claims["iat"] = time.Now().Add(30 * time.Second).Unix()
+
+	if w := postLogoutToken(b, signLogoutToken(t, key, "key-1", claims)); w.Code != http.StatusOK {
+		t.Fatalf("invalid status code: %v: %v", w.Code, w.Body.String())
+	}
+}
+
+func TestBackChannelLogoutConcurrentKeyFetch(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	keys := keysServer(t, key, "key-1")
+	defer keys.Close()
+
+	var (
+		calls   int32
+		release = make(chan struct{})
+	)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		keys.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	var releaseOnce sync.Once
+
+	// Don't leave the fetch blocked if the test fails.
+
+	defer releaseOnce.Do(func() { close(release) })
+
+	b := newBackChannelLogout(t, s.URL, func(ctx context.Context, lt *session.LogoutToken) error {
+		return nil
+	})
+
+	const n = 5
+
+	var wg sync.WaitGroup
+
+	codes := make([]int, n)
+
+	for i := 0; i < n; i++ {
+		token := signLogoutToken(t, key, "key-1", logoutClaims(fmt.Sprintf("jti-%d", i)))
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = postLogoutToken(b, token).Code
+		}(i)
+	}
+
+	// Give the requests the time to join the fetch in progress.
+
+	time.Sleep(100 * time.Millisecond)
+
+	// A request giving up doesn't wait for the fetch to complete.
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	form := url.Values{"logout_token": {signLogoutToken(t, key, "key-1", logoutClaims("jti-canceled"))}}
+
+	r := httptest.NewRequest(http.MethodPost, "/backchannel-logout", strings.NewReader(form.Encode())).WithContext(ctx)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	done := make(chan int)
+
+	go func() {
+		w := httptest.NewRecorder()
+		b.ServeHTTP(w, r)
+		done <- w.Code
+	}()
+
+	select {
+	case code := <-done:
+		if code != http.StatusBadRequest {
+			t.Fatalf("invalid status code: %v", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("request blocked by the key fetch")
+	}
+
+	releaseOnce.Do(func() { close(release) })
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("invalid key fetches: %v", got)
+	}
+
+	for _, code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("invalid status code: %v", code)
+		}
+	}
+}
+
+func TestBackChannelLogoutServerStore(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s := keysServer(t, key, "key-1")
+	defer s.Close()
+
+	store, err := session.NewServerStore(&session.ServerStoreConfig{
+		Backend: session.NewMemoryBackend(),
+	})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	// The ID token of the login carries the ID of the IMS session.
+
+	idToken := signLogoutToken(t, key, "key-1", jwt.MapClaims{"sub": "user-id", "sid": "session-id"})
+
+	ended := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), session.New(&ims.TokenResponse{
+		AccessToken: "access-token",
+		IDToken:     idToken,
+	}))
+
+	other := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{
+		AccessToken: "other-token",
+		UserID:      "user-id",
+		SessionID:   "other-session-id",
+	})
+
+	b := newBackChannelLogout(t, s.URL, store.Logout)
+
+	if w := postLogoutToken(b, signLogoutToken(t, key, "key-1", logoutClaims("jti-1"))); w.Code != http.StatusOK {
+		t.Fatalf("invalid status code: %v: %v", w.Code, w.Body.String())
+	}
+
+	if got, err := store.Load(requestWithCookies(ended)); err != nil || got != nil {
+		t.Fatalf("session not terminated: %+v, %v", got, err)
+	}
+
+	// Only the session identified by the token is terminated.
+
+	if got, err := store.Load(requestWithCookies(other)); err != nil || got == nil {
+		t.Fatalf("other session terminated: %v", err)
+	}
+}
+
+func TestBackChannelLogoutHookFailure(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s := keysServer(t, key, "key-1")
+	defer s.Close()
+
+	fail := true
+
+	b := newBackChannelLogout(t, s.URL, func(ctx context.Context, lt *session.LogoutToken) error {
+		if fail {
+			return fmt.Errorf("store unavailable")
+		}
+		return nil
+	})
+
+	token := signLogoutToken(t, key, "key-1", logoutClaims("jti-1"))
+
+	if w := postLogoutToken(b, token); w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid status code: %v", w.Code)
+	}
+
+	// A token whose logout failed can be delivered again.
+
+	fail = false
+
+	if w := postLogoutToken(b, token); w.Code != http.StatusOK {
+		t.Fatalf("invalid status code: %v", w.Code)
+	}
+}
+
+func TestBackChannelLogoutMethod(t *testing.T) {
+	b := newBackChannelLogout(t, "http://127.0.0.1", func(ctx context.Context, lt *session.LogoutToken) error {
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/backchannel-logout", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("invalid status code: %v", w.Code)
+	}
+}
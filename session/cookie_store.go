@@ -0,0 +1,252 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCookieName   = "ims_session"
+	defaultCookieMaxAge = 24 * time.Hour
+
+	// cookieChunkSize is the maximum size of the value of a cookie. Browsers
+	// limit the size of a cookie, including its name and attributes, to
+	// about 4096 bytes.
+	cookieChunkSize = 3800
+	// maxCookieChunks bounds the size of a session.
+	maxCookieChunks = 10
+)
+
+// CookieStoreConfig is the configuration for a CookieStore.
+type CookieStoreConfig struct {
+	// Keys are the AES keys, 16, 24 or 32 bytes long each. The first key
+	// encrypts new cookies, while every key is used to decrypt them, so that
+	// keys can be rotated without ending the existing sessions. At least one
+	// key is required.
+	Keys [][]byte
+	// Name is the prefix of the names of the cookies. If not provided, it
+	// defaults to "ims_session".
+	Name string
+	// Path is the path of the cookies. If not provided, it defaults to "/".
+	Path string
+	// Domain is the domain of the cookies. Optional.
+	Domain string
+	// MaxAge is the lifetime of the cookies. If not provided, it defaults to
+	// one day.
+	MaxAge time.Duration
+	// InsecureCookie sends the cookies over plain HTTP too. Only meant for
+	// local development.
+	InsecureCookie bool
+}
+
+// CookieStore stores sessions in the browser of the user, in cookies
+// encrypted and authenticated with AES-GCM. Sessions larger than a single
+// cookie are split in chunks named after Name with a numeric suffix.
+type CookieStore struct {
+	aeads          []cipher.AEAD
+	name           string
+	path           string
+	domain         string
+	maxAge         time.Duration
+	insecureCookie bool
+}
+
+// NewCookieStore creates a new CookieStore.
+func NewCookieStore(cfg *CookieStoreConfig) (*CookieStore, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("missing keys")
+	}
+
+	aeads := make([]cipher.AEAD, len(cfg.Keys))
+
+	for i, key := range cfg.Keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key at index %d: %v", i, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key at index %d: %v", i, err)
+		}
+
+		aeads[i] = aead
+	}
+
+	if cfg.MaxAge < 0 {
+		return nil, fmt.Errorf("invalid max age: %v", cfg.MaxAge)
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = defaultCookieName
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = "/"
+	}
+
+	maxAge := cfg.MaxAge
+	if maxAge == 0 {
+		maxAge = defaultCookieMaxAge
+	}
+
+	return &CookieStore{
+		aeads:          aeads,
+		name:           name,
+		path:           path,
+		domain:         cfg.Domain,
+		maxAge:         maxAge,
+		insecureCookie: cfg.InsecureCookie,
+	}, nil
+}
+
+// Load implements Store.
+func (c *CookieStore) Load(r *http.Request) (*Session, error) {
+	var chunks []string
+
+	for i := 0; i < maxCookieChunks; i++ {
+		cookie, err := r.Cookie(c.chunkName(i))
+		if err != nil {
+			break
+		}
+		chunks = append(chunks, cookie.Value)
+	}
+
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(strings.Join(chunks, ""))
+	if err != nil {
+		return nil, fmt.Errorf("decode session cookie: %v", err)
+	}
+
+	plaintext, err := c.decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt session cookie: %v", err)
+	}
+
+	var s Session
+
+	if err := json.Unmarshal(plaintext, &s); err != nil {
+		return nil, fmt.Errorf("decode session cookie: %v", err)
+	}
+
+	return &s, nil
+}
+
+// Save implements Store.
+func (c *CookieStore) Save(w http.ResponseWriter, r *http.Request, s *Session) error {
+	plaintext, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode session: %v", err)
+	}
+
+	ciphertext, err := c.encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("encrypt session: %v", err)
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(ciphertext)
+
+	n := (len(value) + cookieChunkSize - 1) / cookieChunkSize
+	if n > maxCookieChunks {
+		return fmt.Errorf("session too large: %d bytes", len(value))
+	}
+
+	for i := 0; i < n; i++ {
+		end := (i + 1) * cookieChunkSize
+		if end > len(value) {
+			end = len(value)
+		}
+		http.SetCookie(w, c.cookie(c.chunkName(i), value[i*cookieChunkSize:end], int(c.maxAge/time.Second)))
+	}
+
+	// Remove the chunks of a previous, larger session.
+	c.deleteChunks(w, r, n)
+
+	return nil
+}
+
+// Delete implements Store.
+func (c *CookieStore) Delete(w http.ResponseWriter, r *http.Request) error {
+	c.deleteChunks(w, r, 0)
+	return nil
+}
+
+func (c *CookieStore) deleteChunks(w http.ResponseWriter, r *http.Request, from int) {
+	for i := from; i < maxCookieChunks; i++ {
+		if _, err := r.Cookie(c.chunkName(i)); err != nil {
+			break
+		}
+		http.SetCookie(w, c.cookie(c.chunkName(i), "", -1))
+	}
+}
+
+func (c *CookieStore) chunkName(i int) string {
+	return c.name + "_" + strconv.Itoa(i)
+}
+
+func (c *CookieStore) cookie(name, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     c.path,
+		Domain:   c.domain,
+		MaxAge:   maxAge,
+		Secure:   !c.insecureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// encrypt returns the nonce followed by the sealed plaintext. The name of the
+// cookie is authenticated too, so that a value can't be moved to another
+// cookie.
+func (c *CookieStore) encrypt(plaintext []byte) ([]byte, error) {
+	aead := c.aeads[0]
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %v", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(c.name)), nil
+}
+
+func (c *CookieStore) decrypt(ciphertext []byte) ([]byte, error) {
+	for _, aead := range c.aeads {
+		if len(ciphertext) < aead.NonceSize() {
+			return nil, fmt.Errorf("ciphertext too short")
+		}
+
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+
+		if plaintext, err := aead.Open(nil, nonce, sealed, []byte(c.name)); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no key can decrypt the session")
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/session"
+)
+
+func newCookieStore(t *testing.T, keys ...[]byte) *session.CookieStore {
+	t.Helper()
+
+	s, err := session.NewCookieStore(&session.CookieStoreConfig{Keys: keys})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	return s
+}
+
+// saveSession saves the session and returns the cookies set in the response.
+func saveSession(t *testing.T, store session.Store, r *http.Request, s *session.Session) []*http.Cookie {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+
+	if err := store.Save(w, r, s); err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+
+	return w.Result().Cookies()
+}
+
+func requestWithCookies(cookies []*http.Cookie) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for _, c := range cookies {
+		if c.MaxAge >= 0 {
+			r.AddCookie(c)
+		}
+	}
+
+	return r
+}
+
+func TestCookieStore(t *testing.T) {
+	store := newCookieStore(t, bytes.Repeat([]byte("k"), 32))
+
+	want := &session.Session{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).Truncate(time.Second),
+		UserID:       "user-id",
+	}
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), want)
+
+	if len(cookies) != 1 {
+		t.Fatalf("invalid cookies: %v", cookies)
+	}
+	if !cookies[0].HttpOnly || !cookies[0].Secure || cookies[0].SameSite != http.SameSiteLaxMode {
+		t.Fatalf("invalid cookie attributes: %v", cookies[0])
+	}
+	if strings.Contains(cookies[0].Value, "access-token") {
+		t.Fatalf("cookie not encrypted")
+	}
+
+	got, err := store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got == nil || got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || !got.ExpiresAt.Equal(want.ExpiresAt) || got.UserID != want.UserID {
+		t.Fatalf("invalid session: %+v", got)
+	}
+}
+
+func TestCookieStoreChunks(t *testing.T) {
+	store := newCookieStore(t, bytes.Repeat([]byte("k"), 32))
+
+	large := &session.Session{AccessToken: strings.Repeat("a", 10000)}
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), large)
+	if len(cookies) < 3 {
+		t.Fatalf("session not chunked: %d cookies", len(cookies))
+	}
+
+	got, err := store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got == nil || got.AccessToken != large.AccessToken {
+		t.Fatalf("invalid session")
+	}
+
+	// Saving a smaller session removes the stale chunks.
+
+	cookies = saveSession(t, store, requestWithCookies(cookies), &session.Session{AccessToken: "small"})
+
+	var deleted int
+
+	for _, c := range cookies {
+		if c.MaxAge < 0 {
+			deleted++
+		}
+	}
+	if deleted != len(cookies)-1 {
+		t.Fatalf("stale chunks not deleted: %v", cookies)
+	}
+}
+
+func TestCookieStoreKeyRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte("o"), 32)
+	newKey := bytes.Repeat([]byte("n"), 32)
+
+	cookies := saveSession(t, newCookieStore(t, oldKey), httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{AccessToken: "token"})
+
+	got, err := newCookieStore(t, newKey, oldKey).Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got == nil || got.AccessToken != "token" {
+		t.Fatalf("invalid session: %+v", got)
+	}
+
+	if _, err := newCookieStore(t, newKey).Load(requestWithCookies(cookies)); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestCookieStoreTampered(t *testing.T) {
+	store := newCookieStore(t, bytes.Repeat([]byte("k"), 32))
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{AccessToken: "token"})
+	cookies[0].Value = "A" + cookies[0].Value[1:]
+
+	if _, err := store.Load(requestWithCookies(cookies)); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestNewCookieStoreInvalidKey(t *testing.T) {
+	if _, err := session.NewCookieStore(&session.CookieStoreConfig{}); err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, err := session.NewCookieStore(&session.CookieStoreConfig{Keys: [][]byte{[]byte("short")}}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+const (
+	defaultRefreshMargin  = 5 * time.Minute
+	defaultRefreshBackoff = time.Minute
+	// refreshTimeout bounds a refresh shared by concurrent requests.
+	refreshTimeout = 30 * time.Second
+	// refreshResultTTL is how long the result of a successful refresh is
+	// reused for requests still carrying the old refresh token.
+	refreshResultTTL = time.Minute
+)
+
+// ManagerConfig is the configuration for a Manager.
+type ManagerConfig struct {
+	// Store loads and saves the sessions. This field is required.
+	Store Store
+	// Client is the IMS client used to refresh the access tokens. This field
+	// is required.
+	Client *ims.Client
+	// ClientID is the client ID. This field is required.
+	ClientID string
+	// ClientSecret is the client secret. Either ClientSecret or ClientAuth
+	// is required.
+	ClientSecret string
+	// ClientAuth is the method used to authenticate the client. If provided,
+	// ClientSecret must be empty.
+	ClientAuth ims.ClientAuthenticator
+	// RefreshMargin is how long before its expiration an access token is
+	// refreshed. If not provided, it defaults to five minutes.
+	RefreshMargin time.Duration
+	// RefreshBackoff is how long a refresh token isn't used again after a
+	// failed refresh. If not provided, it defaults to one minute.
+	RefreshBackoff time.Duration
+}
+
+// Manager loads the session of every request, refreshing its access token
+// when it is about to expire. Concurrent requests of the same session share a
+// single refresh, so that a refresh token rotated by IMS is only used once.
+type Manager struct {
+	store          Store
+	client         *ims.Client
+	clientID       string
+	clientSecret   string
+	clientAuth     ims.ClientAuthenticator
+	refreshMargin  time.Duration
+	refreshBackoff time.Duration
+
+	mu        sync.Mutex
+	refreshes map[[sha256.Size]byte]*refreshCall
+}
+
+// refreshCall is a refresh in progress or recently completed. The waiters
+// block on done and then read res and err.
+type refreshCall struct {
+	done     chan struct{}
+	res      *ims.RefreshTokenResponse
+	err      error
+	finished time.Time
+}
+
+// NewManager creates a new Manager.
+func NewManager(cfg *ManagerConfig) (*Manager, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("missing store")
+	}
+
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("missing client")
+	}
+
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("missing client ID")
+	}
+
+	if cfg.ClientSecret == "" && cfg.ClientAuth == nil {
+		return nil, fmt.Errorf("missing client secret")
+	}
+
+	if cfg.ClientSecret != "" && cfg.ClientAuth != nil {
+		return nil, fmt.Errorf("client secret and client authenticator are mutually exclusive")
+	}
+
+	if cfg.RefreshMargin < 0 {
+		return nil, fmt.Errorf("invalid refresh margin: %v", cfg.RefreshMargin)
+	}
+
+	if cfg.RefreshBackoff < 0 {
+		return nil, fmt.Errorf("invalid refresh backoff: %v", cfg.RefreshBackoff)
+	}
+
+	refreshMargin := cfg.RefreshMargin
+	if refreshMargin == 0 {
+		refreshMargin = defaultRefreshMargin
+	}
+
+	refreshBackoff := cfg.RefreshBackoff
+	if refreshBackoff == 0 {
+		refreshBackoff = defaultRefreshBackoff
+	}
+
+	return &Manager{
+		store:          cfg.Store,
+		client:         cfg.Client,
+		clientID:       cfg.ClientID,
+		clientSecret:   cfg.ClientSecret,
+		clientAuth:     cfg.ClientAuth,
+		refreshMargin:  refreshMargin,
+		refreshBackoff: refreshBackoff,
+		refreshes:      map[[sha256.Size]byte]*refreshCall{},
+	}, nil
+}
+
+// Middleware loads the session of the request and stores it in the context of
+// the request passed to next, where it can be retrieved with FromContext. The
+// access token is stored in the context too, via ims.WithAccessToken.
+//
+// If the access token expires within the refresh margin and the session has a
+// refresh token, the access token is refreshed and the session saved before
+// next is called. If the refresh fails, the session is kept as long as its
+// access token is still valid, and deleted otherwise. A failed refresh is not
+// retried before RefreshBackoff elapses. Requests without a valid session are
+// passed to next unchanged.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := m.store.Load(r)
+		if err != nil || s == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s, refreshed := m.refresh(r.Context(), s)
+		if s == nil {
+			_ = m.store.Delete(w, r)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if refreshed {
+			if err := m.update(w, r, s); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(newContext(r.Context(), s)))
+	})
+}
+
+// refresh returns the session with a fresh access token, and whether the token
+// was refreshed. It returns nil if the session can't be used anymore.
+func (m *Manager) refresh(ctx context.Context, s *Session) (*Session, bool) {
+	now := time.Now()
+
+	if s.ExpiresAt.Sub(now) > m.refreshMargin {
+		return s, false
+	}
+
+	if s.RefreshToken == "" {
+		if now.Before(s.ExpiresAt) {
+			return s, false
+		}
+		return nil, false
+	}
+
+	res, err := m.refreshToken(ctx, s.RefreshToken)
+	if err != nil {
+		if now.Before(s.ExpiresAt) {
+			return s, false
+		}
+		return nil, false
+	}
+
+	refreshed := *s
+	refreshed.AccessToken = res.AccessToken
+	refreshed.ExpiresAt = now.Add(res.ExpiresIn)
+
+	if res.RefreshToken != "" {
+		refreshed.RefreshToken = res.RefreshToken
+	}
+
+	return &refreshed, true
+}
+
+// update stores a refreshed session, in place if the store supports it.
+func (m *Manager) update(w http.ResponseWriter, r *http.Request, s *Session) error {
+	if u, ok := m.store.(Updater); ok {
+		return u.Update(w, r, s)
+	}
+	return m.store.Save(w, r, s)
+}
+
+// refreshToken refreshes the access token, unless a refresh with the same
+// refresh token is in progress or completed recently, in which case its result
+// is returned. The refresh isn't bound to ctx, so that a request giving up
+// doesn't fail the others.
+func (m *Manager) refreshToken(ctx context.Context, refreshToken string) (*ims.RefreshTokenResponse, error) {
+	key := sha256.Sum256([]byte(refreshToken))
+
+	m.mu.Lock()
+
+	m.pruneRefreshes(time.Now())
+
+	call, ok := m.refreshes[key]
+	if !ok {
+		call = &refreshCall{done: make(chan struct{})}
+		m.refreshes[key] = call
+		go m.doRefresh(context.WithoutCancel(ctx), call, refreshToken)
+	}
+
+	m.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.res, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *Manager) doRefresh(ctx context.Context, call *refreshCall, refreshToken string) {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	res, err := m.client.RefreshTokenWithContext(ctx, &ims.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+		ClientID:     m.clientID,
+		ClientSecret: m.clientSecret,
+		ClientAuth:   m.clientAuth,
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	call.res, call.err = res, err
+	call.finished = time.Now()
+
+	close(call.done)
+}
+
+// pruneRefreshes forgets the successful refreshes older than
+// refreshResultTTL, and the failed ones older than the backoff.
+func (m *Manager) pruneRefreshes(now time.Time) {
+	for key, call := range m.refreshes {
+		if call.finished.IsZero() {
+			continue
+		}
+
+		ttl := refreshResultTTL
+		if call.err != nil {
+			ttl = m.refreshBackoff
+		}
+
+		if now.Sub(call.finished) > ttl {
+			delete(m.refreshes, key)
+		}
+	}
+}
+
+// Save stores the session, typically after a login, and returns a copy of the
+// request whose context carries the session.
+func (m *Manager) Save(w http.ResponseWriter, r *http.Request, s *Session) (*http.Request, error) {
+	if err := m.store.Save(w, r, s); err != nil {
+		return nil, fmt.Errorf("save session: %v", err)
+	}
+
+	return r.WithContext(newContext(r.Context(), s)), nil
+}
+
+// Delete removes the session of the request, typically at logout.
+func (m *Manager) Delete(w http.ResponseWriter, r *http.Request) error {
+	if err := m.store.Delete(w, r); err != nil {
+		return fmt.Errorf("delete session: %v", err)
+	}
+
+	return nil
+}
+
+func newContext(ctx context.Context, s *Session) context.Context {
+	ctx = context.WithValue(ctx, contextKeySession, s)
+	return ims.WithAccessToken(ctx, s.AccessToken)
+}
@@ -0,0 +1,377 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/adobe/ims-go/session"
+)
+
+// refreshServer returns a fake IMS token endpoint refreshing tokens. It fails
+// if fail is true, and counts the requests in calls.
+func refreshServer(t *testing.T, fail *bool, calls *int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+
+		if *fail {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if v := r.PostForm.Get("refresh_token"); v != "refresh-token" {
+			t.Fatalf("invalid refresh token: %v", v)
+		}
+
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "new-access-token",
+			"refresh_token": "new-refresh-token",
+			"expires_in":    3600,
+		}); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+}
+
+func newManager(t *testing.T, imsURL string, store session.Store) *session.Manager {
+	t.Helper()
+
+	client, err := ims.NewClient(&ims.ClientConfig{URL: imsURL})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	m, err := session.NewManager(&session.ManagerConfig{
+		Store:        store,
+		Client:       client,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+
+	return m
+}
+
+// serveMiddleware calls the middleware with the given cookies and returns the
+// session seen by the handler, the access token in the context, and the
+// response cookies.
+func serveMiddleware(m *session.Manager, cookies []*http.Cookie) (*session.Session, string, []*http.Cookie) {
+	var (
+		got   *session.Session
+		token string
+	)
+
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = session.FromContext(r.Context())
+		token, _ = ims.AccessTokenFromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, requestWithCookies(cookies))
+
+	return got, token, w.Result().Cookies()
+}
+
+func TestManagerMiddleware(t *testing.T) {
+	var (
+		fail  bool
+		calls int
+	)
+
+	s := refreshServer(t, &fail, &calls)
+	defer s.Close()
+
+	store := newCookieStore(t, bytes.Repeat([]byte("k"), 32))
+
+	tests := []struct {
+		name        string
+		session     *session.Session
+		fail        bool
+		wantToken   string
+		wantCalls   int
+		wantCookies int
+		wantDeleted bool
+	}{
+		{
+			name:      "valid token",
+			session:   &session.Session{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresAt: time.Now().Add(time.Hour)},
+			wantToken: "access-token",
+		},
+		{
+			name:        "refreshed token",
+			session:     &session.Session{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresAt: time.Now().Add(time.Minute)},
+			wantToken:   "new-access-token",
+			wantCalls:   1,
+			wantCookies: 1,
+		},
+		{
+			name:      "refresh failure with valid token",
+			session:   &session.Session{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresAt: time.Now().Add(time.Minute)},
+			fail:      true,
+			wantToken: "access-token",
+			wantCalls: 1,
+		},
+		{
+			name:        "refresh failure with expired token",
+			session:     &session.Session{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresAt: time.Now().Add(-time.Minute)},
+			fail:        true,
+			wantCalls:   1,
+			wantCookies: 1,
+			wantDeleted: true,
+		},
+		{
+			name:        "expired token without refresh token",
+			session:     &session.Session{AccessToken: "access-token", ExpiresAt: time.Now().Add(-time.Minute)},
+			wantCookies: 1,
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fail, calls = tt.fail, 0
+
+			// The results of refreshes are kept by the manager.
+			m := newManager(t, s.URL, store)
+
+			cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), tt.session)
+
+			got, token, resCookies := serveMiddleware(m, cookies)
+
+			if calls != tt.wantCalls {
+				t.Fatalf("invalid refresh calls: %v", calls)
+			}
+			if token != tt.wantToken {
+				t.Fatalf("invalid access token: %v", token)
+			}
+			if tt.wantToken != "" && (got == nil || got.AccessToken != tt.wantToken) {
+				t.Fatalf("invalid session: %+v", got)
+			}
+			if tt.wantToken == "" && got != nil {
+				t.Fatalf("unexpected session: %+v", got)
+			}
+			if len(resCookies) != tt.wantCookies {
+				t.Fatalf("invalid cookies: %v", resCookies)
+			}
+			if tt.wantDeleted && resCookies[0].MaxAge >= 0 {
+				t.Fatalf("session not deleted")
+			}
+		})
+	}
+}
+
+func TestManagerMiddlewareRefreshPersisted(t *testing.T) {
+	var (
+		fail  bool
+		calls int
+	)
+
+	s := refreshServer(t, &fail, &calls)
+	defer s.Close()
+
+	store := newCookieStore(t, bytes.Repeat([]byte("k"), 32))
+	m := newManager(t, s.URL, store)
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Minute),
+		UserID:       "user-id",
+	})
+
+	_, _, cookies = serveMiddleware(m, cookies)
+
+	got, err := store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got.AccessToken != "new-access-token" || got.RefreshToken != "new-refresh-token" || got.UserID != "user-id" {
+		t.Fatalf("invalid session: %+v", got)
+	}
+	if time.Until(got.ExpiresAt) < 59*time.Minute {
+		t.Fatalf("invalid expiration: %v", got.ExpiresAt)
+	}
+}
+
+func TestManagerMiddlewareRefreshKeepsSessionID(t *testing.T) {
+	var (
+		fail  bool
+		calls int
+	)
+
+	s := refreshServer(t, &fail, &calls)
+	defer s.Close()
+
+	store, err := session.NewServerStore(&session.ServerStoreConfig{
+		Backend: session.NewMemoryBackend(),
+	})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	m := newManager(t, s.URL, store)
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	})
+
+	_, token, refreshed := serveMiddleware(m, cookies)
+	if token != "new-access-token" {
+		t.Fatalf("invalid access token: %v", token)
+	}
+
+	// Requests sent concurrently with the old cookie keep working.
+
+	if len(refreshed) != 1 || refreshed[0].Value != cookies[0].Value {
+		t.Fatalf("session ID changed")
+	}
+
+	got, err := store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got == nil || got.AccessToken != "new-access-token" {
+		t.Fatalf("invalid session: %+v", got)
+	}
+}
+
+func TestManagerMiddlewareConcurrentRefresh(t *testing.T) {
+	var (
+		calls   int32
+		release = make(chan struct{})
+	)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		<-release
+
+		_, _ = w.Write([]byte(`{"access_token":"new-access-token","refresh_token":"new-refresh-token","expires_in":3600}`))
+	}))
+	defer s.Close()
+
+	store := newCookieStore(t, bytes.Repeat([]byte("k"), 32))
+	m := newManager(t, s.URL, store)
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	})
+
+	const n = 5
+
+	var wg sync.WaitGroup
+
+	tokens := make([]string, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, tokens[i], _ = serveMiddleware(m, cookies)
+		}(i)
+	}
+
+	// Give the requests the time to join the refresh in progress.
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("invalid refresh calls: %v", got)
+	}
+
+	for _, token := range tokens {
+		if token != "new-access-token" {
+			t.Fatalf("invalid access token: %v", token)
+		}
+	}
+
+	// A request still carrying the old refresh token reuses the result.
+
+	if _, token, _ := serveMiddleware(m, cookies); token != "new-access-token" || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("refresh not reused: %v", token)
+	}
+}
+
+func TestManagerMiddlewareRefreshBackoff(t *testing.T) {
+	var (
+		fail  = true
+		calls int
+	)
+
+	s := refreshServer(t, &fail, &calls)
+	defer s.Close()
+
+	store := newCookieStore(t, bytes.Repeat([]byte("k"), 32))
+	m := newManager(t, s.URL, store)
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, token, _ := serveMiddleware(m, cookies); token != "access-token" {
+			t.Fatalf("invalid access token: %v", token)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("invalid refresh calls: %v", calls)
+	}
+}
+
+func TestNewManagerInvalidRefreshBackoff(t *testing.T) {
+	client, err := ims.NewClient(&ims.ClientConfig{URL: "http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := session.NewManager(&session.ManagerConfig{
+		Store:          newCookieStore(t, bytes.Repeat([]byte("k"), 32)),
+		Client:         client,
+		ClientID:       "client-id",
+		RefreshBackoff: -time.Second,
+	}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestManagerMiddlewareNoSession(t *testing.T) {
+	m := newManager(t, "http://127.0.0.1", newCookieStore(t, bytes.Repeat([]byte("k"), 32)))
+
+	got, token, cookies := serveMiddleware(m, nil)
+	if got != nil || token != "" || len(cookies) != 0 {
+		t.Fatalf("unexpected session")
+	}
+}
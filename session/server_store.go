@@ -0,0 +1,379 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Backend persists sessions on the server side, e.g. in a database or a
+// distributed cache.
+type Backend interface {
+	// Get returns the session with the given ID, or nil if there is none.
+	Get(ctx context.Context, id string) (*Session, error)
+	// Set stores the session with the given ID for the given time.
+	Set(ctx context.Context, id string, s *Session, ttl time.Duration) error
+	// Replace stores the session with the given ID for the given time, only
+	// if a session with that ID exists. It returns false if there is none.
+	// The check and the write must be atomic, so that a session deleted
+	// concurrently, e.g. by a logout, isn't brought back.
+	Replace(ctx context.Context, id string, s *Session, ttl time.Duration) (bool, error)
+	// Delete removes the session with the given ID, if any.
+	Delete(ctx context.Context, id string) error
+	// DeleteBySessionID removes the sessions whose SessionID is sid.
+	DeleteBySessionID(ctx context.Context, sid string) error
+	// DeleteByUserID removes the sessions whose UserID is userID.
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// ServerStoreConfig is the configuration for a ServerStore.
+type ServerStoreConfig struct {
+	// Backend persists the sessions. This field is required.
+	Backend Backend
+	// Name is the name of the cookie holding the session ID. If not
+	// provided, it defaults to "ims_session".
+	Name string
+	// Path is the path of the cookie. If not provided, it defaults to "/".
+	Path string
+	// Domain is the domain of the cookie. Optional.
+	Domain string
+	// MaxAge is the lifetime of the sessions. If not provided, it defaults
+	// to one day.
+	MaxAge time.Duration
+	// InsecureCookie sends the cookie over plain HTTP too. Only meant for
+	// local development.
+	InsecureCookie bool
+}
+
+// ServerStore stores sessions in a Backend. The browser of the user only
+// holds a random session ID in a cookie.
+type ServerStore struct {
+	backend        Backend
+	name           string
+	path           string
+	domain         string
+	maxAge         time.Duration
+	insecureCookie bool
+}
+
+// NewServerStore creates a new ServerStore.
+func NewServerStore(cfg *ServerStoreConfig) (*ServerStore, error) {
+	if cfg.Backend == nil {
+		return nil, fmt.Errorf("missing backend")
+	}
+
+	if cfg.MaxAge < 0 {
+		return nil, fmt.Errorf("invalid max age: %v", cfg.MaxAge)
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = defaultCookieName
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = "/"
+	}
+
+	maxAge := cfg.MaxAge
+	if maxAge == 0 {
+		maxAge = defaultCookieMaxAge
+	}
+
+	return &ServerStore{
+		backend:        cfg.Backend,
+		name:           name,
+		path:           path,
+		domain:         cfg.Domain,
+		maxAge:         maxAge,
+		insecureCookie: cfg.InsecureCookie,
+	}, nil
+}
+
+// Load implements Store.
+func (s *ServerStore) Load(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(s.name)
+	if err != nil {
+		return nil, nil
+	}
+
+	sess, err := s.backend.Get(r.Context(), cookie.Value)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %v", err)
+	}
+
+	return sess, nil
+}
+
+// Save implements Store. The session always gets a new random ID, and the
+// session previously identified by the cookie of the request, if any, is
+// deleted, so that an ID planted in the browser of a user before the login
+// can't be used to access the session (session fixation).
+func (s *ServerStore) Save(w http.ResponseWriter, r *http.Request, sess *Session) error {
+	data := make([]byte, 32)
+
+	if _, err := rand.Read(data); err != nil {
+		return fmt.Errorf("generate session ID: %v", err)
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(data)
+
+	if err := s.backend.Set(r.Context(), id, sess, s.maxAge); err != nil {
+		return fmt.Errorf("set session: %v", err)
+	}
+
+	if cookie, err := r.Cookie(s.name); err == nil {
+		if err := s.backend.Delete(r.Context(), cookie.Value); err != nil {
+			return fmt.Errorf("delete previous session: %v", err)
+		}
+	}
+
+	http.SetCookie(w, s.cookie(id, int(s.maxAge/time.Second)))
+
+	return nil
+}
+
+// Update implements Updater. The session keeps its ID, which must identify
+// an existing session.
+func (s *ServerStore) Update(w http.ResponseWriter, r *http.Request, sess *Session) error {
+	cookie, err := r.Cookie(s.name)
+	if err != nil {
+		return fmt.Errorf("missing session cookie")
+	}
+
+	ok, err := s.backend.Replace(r.Context(), cookie.Value, sess, s.maxAge)
+	if err != nil {
+		return fmt.Errorf("replace session: %v", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("unknown session")
+	}
+
+	http.SetCookie(w, s.cookie(cookie.Value, int(s.maxAge/time.Second)))
+
+	return nil
+}
+
+// Delete implements Store.
+func (s *ServerStore) Delete(w http.ResponseWriter, r *http.Request) error {
+	cookie, err := r.Cookie(s.name)
+	if err != nil {
+		return nil
+	}
+
+	http.SetCookie(w, s.cookie("", -1))
+
+	if err := s.backend.Delete(r.Context(), cookie.Value); err != nil {
+		return fmt.Errorf("delete session: %v", err)
+	}
+
+	return nil
+}
+
+// Logout terminates the sessions ended by a back-channel logout token. It is
+// meant to be used as the OnLogout hook of a BackChannelLogout. The sessions
+// matching the session ID of the token are deleted if the token has one, and
+// the sessions of its subject otherwise. Sessions are only matched by session
+// ID if they were created from a token response with an ID token.
+func (s *ServerStore) Logout(ctx context.Context, t *LogoutToken) error {
+	if t.SessionID != "" {
+		if err := s.backend.DeleteBySessionID(ctx, t.SessionID); err != nil {
+			return fmt.Errorf("delete sessions: %v", err)
+		}
+		return nil
+	}
+
+	if err := s.backend.DeleteByUserID(ctx, t.Subject); err != nil {
+		return fmt.Errorf("delete sessions: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ServerStore) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     s.name,
+		Value:    value,
+		Path:     s.path,
+		Domain:   s.domain,
+		MaxAge:   maxAge,
+		Secure:   !s.insecureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// MemoryBackend is a Backend keeping the sessions in memory. It is only
+// suitable for applications running as a single instance. Expired sessions
+// are removed when a session is stored.
+type MemoryBackend struct {
+	mu          sync.Mutex
+	sessions    map[string]memorySession
+	bySessionID map[string]map[string]struct{}
+	byUserID    map[string]map[string]struct{}
+}
+
+type memorySession struct {
+	session   Session
+	expiresAt time.Time
+}
+
+// NewMemoryBackend creates a new MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		sessions:    map[string]memorySession{},
+		bySessionID: map[string]map[string]struct{}{},
+		byUserID:    map[string]map[string]struct{}{},
+	}
+}
+
+// Get implements Backend.
+func (b *MemoryBackend) Get(_ context.Context, id string) (*Session, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m, ok := b.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+
+	if !time.Now().Before(m.expiresAt) {
+		b.remove(id)
+		return nil, nil
+	}
+
+	s := m.session
+
+	return &s, nil
+}
+
+// Set implements Backend.
+func (b *MemoryBackend) Set(_ context.Context, id string, s *Session, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sweep()
+	b.set(id, s, ttl)
+
+	return nil
+}
+
+// Replace implements Backend.
+func (b *MemoryBackend) Replace(_ context.Context, id string, s *Session, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sweep()
+
+	if _, ok := b.sessions[id]; !ok {
+		return false, nil
+	}
+
+	b.set(id, s, ttl)
+
+	return true, nil
+}
+
+// Delete implements Backend.
+func (b *MemoryBackend) Delete(_ context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(id)
+
+	return nil
+}
+
+// DeleteBySessionID implements Backend.
+func (b *MemoryBackend) DeleteBySessionID(_ context.Context, sid string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id := range b.bySessionID[sid] {
+		b.remove(id)
+	}
+
+	return nil
+}
+
+// DeleteByUserID implements Backend.
+func (b *MemoryBackend) DeleteByUserID(_ context.Context, userID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id := range b.byUserID[userID] {
+		b.remove(id)
+	}
+
+	return nil
+}
+
+func (b *MemoryBackend) set(id string, s *Session, ttl time.Duration) {
+	b.remove(id)
+
+	b.sessions[id] = memorySession{
+		session:   *s,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	addIndex(b.bySessionID, s.SessionID, id)
+	addIndex(b.byUserID, s.UserID, id)
+}
+
+// sweep removes the expired sessions.
+func (b *MemoryBackend) sweep() {
+	now := time.Now()
+
+	for id, m := range b.sessions {
+		if !now.Before(m.expiresAt) {
+			b.remove(id)
+		}
+	}
+}
+
+func (b *MemoryBackend) remove(id string) {
+	m, ok := b.sessions[id]
+	if !ok {
+		return
+	}
+
+	delete(b.sessions, id)
+	removeIndex(b.bySessionID, m.session.SessionID, id)
+	removeIndex(b.byUserID, m.session.UserID, id)
+}
+
+func addIndex(index map[string]map[string]struct{}, key, id string) {
+	if key == "" {
+		return
+	}
+
+	if index[key] == nil {
+		index[key] = map[string]struct{}{}
+	}
+
+	index[key][id] = struct{}{}
+}
+
+func removeIndex(index map[string]map[string]struct{}, key, id string) {
+	delete(index[key], id)
+
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package session_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adobe/ims-go/session"
+)
+
+func TestServerStore(t *testing.T) {
+	store, err := session.NewServerStore(&session.ServerStoreConfig{
+		Backend: session.NewMemoryBackend(),
+	})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	cookies := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{AccessToken: "token"})
+	if len(cookies) != 1 || cookies[0].Value == "token" {
+		t.Fatalf("invalid cookies: %v", cookies)
+	}
+
+	got, err := store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got == nil || got.AccessToken != "token" {
+		t.Fatalf("invalid session: %+v", got)
+	}
+
+	// Updating the session keeps its ID.
+
+	w := httptest.NewRecorder()
+
+	if err := store.Update(w, requestWithCookies(cookies), &session.Session{AccessToken: "other"}); err != nil {
+		t.Fatalf("update session: %v", err)
+	}
+
+	updated := w.Result().Cookies()
+	if len(updated) != 1 || updated[0].Value != cookies[0].Value {
+		t.Fatalf("session ID changed")
+	}
+
+	got, err = store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got == nil || got.AccessToken != "other" {
+		t.Fatalf("invalid session: %+v", got)
+	}
+
+	w = httptest.NewRecorder()
+
+	if err := store.Delete(w, requestWithCookies(cookies)); err != nil {
+		t.Fatalf("delete session: %v", err)
+	}
+
+	got, err = store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("session not deleted")
+	}
+}
+
+func TestServerStoreSessionFixation(t *testing.T) {
+	store, err := session.NewServerStore(&session.ServerStoreConfig{
+		Backend: session.NewMemoryBackend(),
+	})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	// The attacker plants an ID in the browser of the user, possibly the ID
+	// of their own session.
+
+	planted := saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{AccessToken: "attacker"})
+
+	cookies := saveSession(t, store, requestWithCookies(planted), &session.Session{AccessToken: "user"})
+	if len(cookies) != 1 || cookies[0].Value == planted[0].Value {
+		t.Fatalf("session ID not renewed")
+	}
+
+	got, err := store.Load(requestWithCookies(planted))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("previous session not deleted")
+	}
+
+	got, err = store.Load(requestWithCookies(cookies))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got == nil || got.AccessToken != "user" {
+		t.Fatalf("invalid session: %+v", got)
+	}
+
+	// An unknown ID can't be updated.
+
+	unknown := []*http.Cookie{{Name: "ims_session", Value: "unknown"}}
+
+	if err := store.Update(httptest.NewRecorder(), requestWithCookies(unknown), &session.Session{AccessToken: "user"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestServerStoreUnknownID(t *testing.T) {
+	store, err := session.NewServerStore(&session.ServerStoreConfig{
+		Backend: session.NewMemoryBackend(),
+	})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	got, err := store.Load(requestWithCookies([]*http.Cookie{{Name: "ims_session", Value: "unknown"}}))
+	if err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("unexpected session")
+	}
+}
+
+func TestServerStoreLogoutSubject(t *testing.T) {
+	store, err := session.NewServerStore(&session.ServerStoreConfig{
+		Backend: session.NewMemoryBackend(),
+	})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	var (
+		first  = saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{AccessToken: "first", UserID: "user-id", SessionID: "first-sid"})
+		second = saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{AccessToken: "second", UserID: "user-id"})
+		other  = saveSession(t, store, httptest.NewRequest(http.MethodGet, "/", nil), &session.Session{AccessToken: "other", UserID: "other-id"})
+	)
+
+	// A token without session ID ends every session of its subject.
+
+	if err := store.Logout(context.Background(), &session.LogoutToken{Subject: "user-id"}); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	for _, cookies := range [][]*http.Cookie{first, second} {
+		if got, err := store.Load(requestWithCookies(cookies)); err != nil || got != nil {
+			t.Fatalf("session not terminated: %+v, %v", got, err)
+		}
+	}
+
+	if got, err := store.Load(requestWithCookies(other)); err != nil || got == nil {
+		t.Fatalf("session of other user terminated: %v", err)
+	}
+}
+
+func TestMemoryBackendReplace(t *testing.T) {
+	ctx := context.Background()
+
+	b := session.NewMemoryBackend()
+
+	if ok, err := b.Replace(ctx, "id", &session.Session{AccessToken: "new"}, time.Hour); err != nil || ok {
+		t.Fatalf("unknown session replaced: %v", err)
+	}
+
+	if err := b.Set(ctx, "id", &session.Session{AccessToken: "old", UserID: "user-id"}, time.Hour); err != nil {
+		t.Fatalf("set session: %v", err)
+	}
+
+	if ok, err := b.Replace(ctx, "id", &session.Session{AccessToken: "new", UserID: "user-id"}, time.Hour); err != nil || !ok {
+		t.Fatalf("session not replaced: %v", err)
+	}
+
+	if got, err := b.Get(ctx, "id"); err != nil || got == nil || got.AccessToken != "new" {
+		t.Fatalf("invalid session: %+v, %v", got, err)
+	}
+
+	// A session deleted by a logout isn't brought back.
+
+	if err := b.DeleteByUserID(ctx, "user-id"); err != nil {
+		t.Fatalf("delete sessions: %v", err)
+	}
+
+	if ok, err := b.Replace(ctx, "id", &session.Session{AccessToken: "newer"}, time.Hour); err != nil || ok {
+		t.Fatalf("deleted session replaced: %v", err)
+	}
+
+	if got, err := b.Get(ctx, "id"); err != nil || got != nil {
+		t.Fatalf("unexpected session: %+v, %v", got, err)
+	}
+}
+
+func TestMemoryBackendExpiredSession(t *testing.T) {
+	ctx := context.Background()
+
+	b := session.NewMemoryBackend()
+
+	if err := b.Set(ctx, "expired", &session.Session{AccessToken: "expired"}, time.Millisecond); err != nil {
+		t.Fatalf("set session: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	// The expired session is swept when another session is stored.
+
+	if err := b.Set(ctx, "other", &session.Session{AccessToken: "other"}, time.Hour); err != nil {
+		t.Fatalf("set session: %v", err)
+	}
+
+	if ok, err := b.Replace(ctx, "expired", &session.Session{AccessToken: "new"}, time.Hour); err != nil || ok {
+		t.Fatalf("expired session replaced: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Package session keeps the IMS tokens of the users of a web application
+// across requests. Sessions are stored either in encrypted cookies or in a
+// server-side store, and their access tokens are refreshed transparently as
+// requests come in. Sessions in a server-side store can also be terminated by
+// IMS through back-channel logout requests.
+package session
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/adobe/ims-go/ims"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Session is the data of a user session.
+type Session struct {
+	// AccessToken is the current access token of the user.
+	AccessToken string `json:"at"`
+	// RefreshToken is the refresh token of the user, if any.
+	RefreshToken string `json:"rt,omitempty"`
+	// ExpiresAt is when AccessToken expires.
+	ExpiresAt time.Time `json:"exp"`
+	// UserID is the IMS ID of the user.
+	UserID string `json:"uid,omitempty"`
+	// SessionID is the ID of the IMS session of the user, if known. It is
+	// used to terminate the session on back-channel logout.
+	SessionID string `json:"sid,omitempty"`
+	// Name is the display name of the user. Optional.
+	Name string `json:"name,omitempty"`
+	// Email is the email address of the user. Optional.
+	Email string `json:"email,omitempty"`
+}
+
+// New creates a session from the response of a token request, typically
+// received at the end of a login. If the response contains an ID token, the
+// ID of the IMS session is read from its sid claim. The signature of the ID
+// token isn't verified, because the token was received directly from IMS.
+func New(res *ims.TokenResponse) *Session {
+	s := &Session{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		ExpiresAt:    time.Now().Add(res.ExpiresIn),
+		UserID:       res.UserID,
+	}
+
+	if res.IDToken != "" {
+		var claims struct {
+			jwt.RegisteredClaims
+			SessionID string `json:"sid"`
+		}
+
+		if _, _, err := jwt.NewParser().ParseUnverified(res.IDToken, &claims); err == nil {
+			s.SessionID = claims.SessionID
+
+			if s.UserID == "" {
+				s.UserID = claims.Subject
+			}
+		}
+	}
+
+	return s
+}
+
+// Store loads and saves sessions.
+type Store interface {
+	// Load returns the session of the request, or nil if there is none.
+	Load(r *http.Request) (*Session, error)
+	// Save stores the session. It must be called before the response is
+	// written.
+	Save(w http.ResponseWriter, r *http.Request, s *Session) error
+	// Delete removes the session of the request, if any.
+	Delete(w http.ResponseWriter, r *http.Request) error
+}
+
+// Updater is implemented by the stores that can update an existing session in
+// place. Manager uses it to store refreshed tokens, since Save might do more
+// than needed for a session that already exists, like renewing its ID.
+type Updater interface {
+	// Update stores the session, which must be the session of the request.
+	// It must be called before the response is written.
+	Update(w http.ResponseWriter, r *http.Request, s *Session) error
+}
+
+type contextKey string
+
+var contextKeySession = contextKey("session")
+
+// FromContext returns the session stored in the context by the middleware of
+// a Manager, if any.
+func FromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(contextKeySession).(*Session)
+	return s, ok
+}